@@ -0,0 +1,94 @@
+package gltext
+
+import (
+	"strings"
+)
+
+//Role tells an accessibility backend what kind of widget a node is
+type Role int
+
+const (
+	RoleLabel Role = iota
+	RoleTextInput
+	RolePasswordInput
+	RoleDocument //a block of text read but not edited, such as a TextView
+	RoleLog      //text that grows as things happen, such as a Console
+	RoleGroup    //holds other nodes and nothing else
+)
+
+//AccessNode is what an accessibility backend is told about a widget, as of when it was exported
+type AccessNode struct {
+	Role   Role
+	Name   string //what the widget is announced as, which the app sets through AccessibleName
+	Value  string //the text shown, or edited; never the text of a password input
+	Bounds Rect   //in draw units
+	Hidden bool
+	//Caret and the selection [SelectionStart, SelectionEnd) are rune indices into Value, for inputs
+	Caret, SelectionStart, SelectionEnd int
+	Children                            []AccessNode
+}
+
+//Accessible is a widget that can describe itself to an accessibility backend
+type Accessible interface {
+	AccessNode() AccessNode
+}
+
+//AccessibilityBackend receives the tree of widgets on screen, for passing on to AccessKit or a platform's screen
+//reader API. it is called with the whole tree each time, and may compare it with the last to send only what changed.
+type AccessibilityBackend interface {
+	UpdateTree(root AccessNode)
+}
+
+//ExportAccessibility sends backend a group of the widgets' nodes, in the order given, which should be reading order.
+//call it each frame after the widgets have been updated, or whenever their state changes.
+func ExportAccessibility(backend AccessibilityBackend, widgets ...Accessible) {
+	root := AccessNode{Role: RoleGroup, Children: make([]AccessNode, len(widgets))}
+	for i, w := range widgets {
+		root.Children[i] = w.AccessNode()
+		root.Bounds = root.Bounds.Union(root.Children[i].Bounds)
+	}
+	backend.UpdateTree(root)
+}
+
+func (this *Layout) AccessNode() AccessNode {
+	return AccessNode{Role: RoleLabel, Value: this.text(), Bounds: this.Rect()}
+}
+
+func (this *Label) AccessNode() AccessNode {
+	node := this.Layout.AccessNode()
+	node.Hidden = !this.Visible
+	return node
+}
+
+func (this *TextInput) AccessNode() AccessNode {
+	node := AccessNode{Role: RoleTextInput, Name: this.AccessibleName, Bounds: Rect{this.X, this.Y, this.Width, this.font.cellHeight}}
+	if this.Mask != 0 {
+		node.Role = RolePasswordInput
+		return node
+	}
+	node.Value = this.Text()
+	node.Caret = this.caret
+	node.SelectionStart, node.SelectionEnd = this.Selection()
+	return node
+}
+
+func (this *TextView) AccessNode() AccessNode {
+	return AccessNode{Role: RoleDocument, Name: this.AccessibleName, Value: this.content.text(), Bounds: this.Rect()}
+}
+
+//AccessNode describes the scrollback lines on screen, with the input row as its child
+func (this *Console) AccessNode() AccessNode {
+	last := this.count - this.scroll
+	first := maxInt(last-this.Rows, 0)
+	lines := make([]string, 0, last-first)
+	for i := first; i < last; i++ {
+		lines = append(lines, this.Line(i))
+	}
+	return AccessNode{
+		Role:     RoleLog,
+		Name:     this.AccessibleName,
+		Value:    strings.Join(lines, "\n"),
+		Bounds:   Rect{this.X, this.Y, this.Width, float32(this.Rows+1) * this.font.cellHeight},
+		Children: []AccessNode{this.Input.AccessNode()},
+	}
+}
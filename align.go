@@ -0,0 +1,60 @@
+package gltext
+
+//VerticalAlign chooses what the y coordinate of a layout refers to
+type VerticalAlign int
+
+const (
+	AlignTop      VerticalAlign = iota //the top of the first line, as the quads are drawn
+	AlignMiddle                        //half way down the block of lines
+	AlignBaseline                      //the baseline of the first line
+	AlignBottom                        //the bottom of the last line
+)
+
+//alignVertically moves a layout built with its top at y so that y means what align says
+func (this *Layout) alignVertically(align VerticalAlign) {
+	var dy float32
+	switch align {
+	case AlignMiddle:
+		dy = this.Height() / 2
+	case AlignBaseline:
+		dy = this.baseline()
+	case AlignBottom:
+		dy = this.Height()
+	default:
+		return
+	}
+	this.MoveTo(this.x, this.y+dy)
+}
+
+//Anchor is a point on the rectangle measured around a layout, used to place it without measuring by hand
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+//fractions returns how far across and down the rectangle the anchor is
+func (this Anchor) fractions() (fx, fy float32) {
+	return float32(this%3) / 2, float32(this/3) / 2
+}
+
+//AnchorTo moves the layout so that the anchor point of its Rect is at x, y
+func (this *Layout) AnchorTo(x, y float32, anchor Anchor) *Layout {
+	fx, fy := anchor.fractions()
+	r := this.Rect()
+	return this.MoveTo(x-fx*r.W, y+fy*r.H)
+}
+
+//PrintfAnchor prints so that the anchor point of the text lands on x, y: AnchorCenter at 1, -1 centres it on
+//the screen, and AnchorTopRight at 2, 0 keeps it in the top right corner however wide it gets
+func (this *Font) PrintfAnchor(x, y float32, anchor Anchor, fs string, argv ...interface{}) {
+	this.Layout(0, 0, fs, argv...).AnchorTo(x, y, anchor).Draw()
+}
@@ -0,0 +1,19 @@
+package gltext
+
+//Effect selects per glyph animations, which are evaluated in the vertex shader
+type Effect int
+
+const (
+	Wave    Effect = 1 << iota //glyphs bob up and down in a travelling sine wave
+	Shake                      //glyphs jitter randomly, changing Speed times a second
+	Rainbow                    //glyph color cycles through the hues
+)
+
+//Animation parameterizes Style effects. Advance Time every frame to animate.
+type Animation struct {
+	Effects   Effect
+	Time      float32 //seconds
+	Amplitude float32 //displacement for Wave and Shake, in draw units
+	Speed     float32 //cycles per second
+	Spread    float32 //phase difference between neighbouring glyphs, in cycles
+}
@@ -0,0 +1,41 @@
+package gltext
+
+import "math"
+
+//LayoutArc lays s out on a circle of radius vertical draw units around (cx, cy), starting at angle start radians,
+//counterclockwise from three o'clock. outward text runs clockwise with its tops away from the centre, as on the
+//top of a badge; inward text runs counterclockwise with its tops towards the centre, as on the bottom of one.
+//it has the same limitations as LayoutPath.
+func (this *Font) LayoutArc(cx, cy, radius, start float32, inward bool, style Style, s string) *Layout {
+	style.Background = Background{}
+	style.Decorations = 0
+	layout := this.layoutText(0, 0, 0, style, s, nil)
+	layout.follow(&arc{cx * this.aspect(), cy, radius, start, inward}, 0)
+	return layout
+}
+
+//ArcSpan is the angle in radians that width horizontal draw units of text covers on a circle of radius,
+//so that a label can be centred about an angle by starting it half a span before
+func (this *Font) ArcSpan(width, radius float32) float32 {
+	if radius == 0 {
+		return 0
+	}
+	return width * this.aspect() / radius
+}
+
+type arc struct {
+	cx, cy, radius, start float32
+	inward                bool
+}
+
+func (this *arc) at(d float32) (x, y, angle float32) {
+	if this.radius == 0 {
+		return this.cx, this.cy, 0
+	}
+	theta, turn := this.start-d/this.radius, -math.Pi/2
+	if this.inward {
+		theta, turn = this.start+d/this.radius, math.Pi/2
+	}
+	sin, cos := math.Sincos(float64(theta))
+	return this.cx + this.radius*float32(cos), this.cy + this.radius*float32(sin), theta + float32(turn)
+}
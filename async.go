@@ -0,0 +1,64 @@
+package gltext
+
+import (
+	"sync/atomic"
+)
+
+//FontLoader parses and rasterizes a font in the background; the GL upload happens on the first call to Font
+type FontLoader struct {
+	done   chan struct{}
+	atlas  *atlas
+	font   *Font
+	source fontSource
+	err    error //from reading or parsing the file
+
+	glyphsDone, glyphsTotal int32
+}
+
+func NewFontAsync(fontPath string, scale int32, dpi float64, width, height float32) *FontLoader {
+	loader := &FontLoader{done: make(chan struct{})}
+	go func() {
+		defer close(loader.done)
+		loader.source = newFontSource(fontPath, scale, dpi, width, height)
+		font, err := parseFontFile(fontPath)
+		if err != nil {
+			loader.err = err
+			return
+		}
+		loader.atlas = generateAtlas(font, loader.source, loader.progress)
+	}()
+	return loader
+}
+
+func (this *FontLoader) progress(done, total int) {
+	atomic.StoreInt32(&this.glyphsTotal, int32(total))
+	atomic.StoreInt32(&this.glyphsDone, int32(done))
+}
+
+//Progress returns how many glyphs have been rasterized so far; total is zero until rasterization starts
+func (this *FontLoader) Progress() (done, total int) {
+	return int(atomic.LoadInt32(&this.glyphsDone)), int(atomic.LoadInt32(&this.glyphsTotal))
+}
+
+//Ready reports whether the CPU side of loading has finished, so Font will not block
+func (this *FontLoader) Ready() bool {
+	select {
+	case <-this.done:
+		return true
+	default:
+		return false
+	}
+}
+
+//Font blocks until loading has finished, and returns the error if the file couldn't be read or parsed.
+//It must be called on the GL thread.
+func (this *FontLoader) Font() (*Font, error) {
+	<-this.done
+	if this.err != nil {
+		return nil, this.err
+	}
+	if this.font == nil {
+		this.font = uploadFont(this.source, this.atlas)
+	}
+	return this.font, nil
+}
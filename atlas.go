@@ -0,0 +1,305 @@
+package gltext
+
+import (
+	"code.google.com/p/freetype-go/freetype/truetype"
+	"github.com/go-gl/glh"
+	"github.com/jimarnold/gl"
+	"image"
+	"image/draw"
+	"math"
+)
+
+//MaxAtlasPageSize caps the width and height of each atlas texture, in pixels. the driver's GL_MAX_TEXTURE_SIZE
+//lowers it further where necessary; glyphs that don't fit on one page spill onto more.
+var MaxAtlasPageSize = 4096
+
+//atlas is the CPU side of a font: glyph quads, the rasterized texture pages and the metrics needed to lay out text.
+//glyphs occupy equal sized cells laid out left to right, then top to bottom. every page but the last is full size.
+type atlas struct {
+	coords  []Vector4
+	pages   []*image.RGBA
+	maxSize int //largest width and height of a page
+	offsets []float32
+	ink     []Rect       //tight bounds of each glyph relative to its quad's top left, in draw units
+	runes   map[rune]int //index of each rune's glyph
+	glyphs  []rune       //rune of each glyph
+
+	cellWidth, cellPixels int //cell size in pixels
+	baselinePixels        int
+	sx, sy                float32 //draw units per pixel
+
+	cellHeight float32 //height of every glyph quad, in draw units
+	baseline   float32 //distance from the top of a glyph quad to the baseline, in draw units
+
+	font   *truetype.Font //kept so that glyphs can be rasterized after the atlas is built
+	source fontSource
+
+	clock     uint64   //counts glyph lookups, to order glyphs by when they were last used
+	used      []uint64 //clock at each glyph's last use
+	evictable []bool   //glyphs loaded on demand, which may make way for others
+	compacted uint64   //clock at the last compaction
+	pinned    bool     //a layout is being built, and the glyphs used since pinnedAt are in it
+	pinnedAt  uint64
+}
+
+func newAtlas(img *image.RGBA, cellWidth, cellHeight, baseline int, sx, sy float32) *atlas {
+	return &atlas{
+		pages:          []*image.RGBA{img},
+		maxSize:        MaxAtlasPageSize,
+		runes:          make(map[rune]int),
+		cellWidth:      cellWidth,
+		cellPixels:     cellHeight,
+		baselinePixels: baseline,
+		sx:             sx,
+		sy:             sy,
+		cellHeight:     float32(cellHeight) * sy,
+		baseline:       float32(baseline) * sy,
+	}
+}
+
+//cell returns the page glyph i is on and the top left pixel of its cell there
+func (this *atlas) cell(i int) (*image.RGBA, image.Point) {
+	page := this.pages[this.page(i)]
+	local := i % this.pageCells()
+	return page, cellOrigin(local, columns(page, this.cellWidth), this.cellWidth, this.cellPixels)
+}
+
+//page is the index of the page glyph i is on
+func (this *atlas) page(i int) int {
+	return i / this.pageCells()
+}
+
+//pageCells is how many glyphs a full size page holds
+func (this *atlas) pageCells() int {
+	return maxInt(this.maxSize/this.cellWidth, 1) * maxInt(this.maxSize/this.cellPixels, 1)
+}
+
+func cellOrigin(i, columns, cellWidth, cellHeight int) image.Point {
+	return image.Pt(i%columns*cellWidth, i/columns*cellHeight)
+}
+
+func columns(page *image.RGBA, cellWidth int) int {
+	return maxInt(page.Bounds().Dx()/cellWidth, 1)
+}
+
+func (this *atlas) capacity() int {
+	last := this.pages[len(this.pages)-1]
+	lastCells := columns(last, this.cellWidth) * maxInt(last.Bounds().Dy()/this.cellPixels, 1)
+	return (len(this.pages)-1)*this.pageCells() + lastCells
+}
+
+//atlasSize returns power of two dimensions, roughly square, with room for n cells but no larger than maxSize
+func atlasSize(n, cellWidth, cellHeight, maxSize int) (width, height int) {
+	cellWidth, cellHeight = maxInt(cellWidth, 1), maxInt(cellHeight, 1)
+	width, height = int(glh.Pow2(uint32(cellWidth))), int(glh.Pow2(uint32(cellHeight)))
+	for (width/cellWidth)*(height/cellHeight) < n && (width < maxSize || height < maxSize) {
+		if (width <= height || height >= maxSize) && width < maxSize {
+			width = minInt(width*2, maxSize)
+		} else {
+			height = minInt(height*2, maxSize)
+		}
+	}
+	return width, height
+}
+
+//add copies cell into the next free slot, growing the image if it is full, and reports whether it grew
+func (this *atlas) add(r rune, cell image.Image, advance float32, ink Rect) bool {
+	i := len(this.offsets)
+	grew := false
+	for i >= this.capacity() {
+		this.grow()
+		grew = true
+	}
+	page, origin := this.cell(i)
+	draw.Draw(page, image.Rect(0, 0, this.cellWidth, this.cellPixels).Add(origin), cell, cell.Bounds().Min, draw.Src)
+
+	this.offsets = append(this.offsets, advance)
+	this.ink = append(this.ink, ink)
+	this.used = append(this.used, this.clock)
+	this.evictable = append(this.evictable, false)
+	this.glyphs = append(this.glyphs, r)
+	this.runes[r] = i
+	if grew {
+		this.buildQuads()
+	} else {
+		this.coords = append(this.coords, this.quad(i)...)
+	}
+	return grew
+}
+
+//grow doubles the shorter side of the last page, moving its cells to keep them in order, or starts a new page once
+//the last is as large as it can be; texture coordinates must be rebuilt afterwards
+func (this *atlas) grow() {
+	last := len(this.pages) - 1
+	bounds := this.pages[last].Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w >= this.maxSize && h >= this.maxSize {
+		w, h = atlasSize(1, this.cellWidth, this.cellPixels, this.maxSize)
+		this.pages = append(this.pages, image.NewRGBA(image.Rect(0, 0, w, h)))
+		return
+	}
+	if (w <= h || h >= this.maxSize) && w < this.maxSize {
+		w = minInt(w*2, this.maxSize)
+	} else {
+		h = minInt(h*2, this.maxSize)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	first := last * this.pageCells()
+	cell := image.Rect(0, 0, this.cellWidth, this.cellPixels)
+	for i := first; i < len(this.offsets); i++ {
+		page, from := this.cell(i)
+		to := cellOrigin(i-first, columns(img, this.cellWidth), this.cellWidth, this.cellPixels)
+		draw.Draw(img, cell.Add(to), page, from, draw.Src)
+	}
+	this.pages[last] = img
+}
+
+//rebuild copies the glyphs in keep, in order, into a new atlas whose pages are at most maxSize
+func (this *atlas) rebuild(keep []int, maxSize int) *atlas {
+	width, height := atlasSize(len(keep), this.cellWidth, this.cellPixels, maxSize)
+	a := newAtlas(image.NewRGBA(image.Rect(0, 0, width, height)), this.cellWidth, this.cellPixels, this.baselinePixels, this.sx, this.sy)
+	a.maxSize = maxSize
+	a.font, a.source, a.clock, a.compacted = this.font, this.source, this.clock, this.compacted
+	for _, i := range keep {
+		page, origin := this.cell(i)
+		cell := page.SubImage(image.Rect(0, 0, this.cellWidth, this.cellPixels).Add(origin))
+		a.add(this.glyphs[i], cell, this.offsets[i], this.ink[i])
+		j := len(a.offsets) - 1
+		a.used[j], a.evictable[j] = this.used[i], this.evictable[i]
+	}
+	return a
+}
+
+func (this *atlas) buildQuads() {
+	this.coords = this.coords[:0]
+	for i := range this.offsets {
+		this.coords = append(this.coords, this.quad(i)...)
+	}
+}
+
+func (this *atlas) quad(i int) []Vector4 {
+	page, origin := this.cell(i)
+	gx, gy := float32(origin.X), float32(origin.Y)
+	gw, gh := float32(this.cellWidth), float32(this.cellPixels)
+	texWidth := float32(page.Bounds().Dx())
+	texHeight := float32(page.Bounds().Dy())
+	w := gw * this.sx
+	h := this.cellHeight
+
+	tx1 := gx / texWidth
+	ty1 := gy / texHeight
+	tx2 := (gx + gw) / texWidth
+	ty2 := (gy + gh) / texHeight
+
+	//the x,y coordinates are the same for each quad; only the texture coordinates (stored in z,w) change.
+	//an optimization would be to only store texture coords, but I haven't figured that out yet
+	return []Vector4{{-1, 1, tx1, ty1},
+		{-1 + (w), 1, tx2, ty1},
+		{-1, 1 - (h), tx1, ty2},
+		{-1 + (w), 1 - (h), tx2, ty2}}
+}
+
+//maxTextureSize asks the driver how large a texture may be
+func maxTextureSize() int {
+	size := make([]int32, 1)
+	gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, size)
+	return int(size[0])
+}
+
+//newPageTexture makes a texture for an atlas page, set up for drawing glyphs from
+func newPageTexture() gl.Texture {
+	tex := gl.GenTexture()
+	tex.Bind(gl.TEXTURE_2D)
+	/* Clamping to edges is important to prevent artifacts when scaling */
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	/* Linear filtering usually looks best for text */
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	return tex
+}
+
+//uploadAtlas copies every atlas page and the quad buffer to the GPU, making textures for new pages
+func (this *Font) uploadAtlas() {
+	defer endTrace(beginTrace(PhaseUpload))
+	gl.ActiveTexture(gl.TEXTURE0)
+	for p, img := range this.atlas.pages {
+		this.stats.BytesUploaded += this.pageBytes(p)
+		if this.dsa {
+			this.uploadPageDirect(p)
+			continue
+		}
+		if p == len(this.textures) {
+			this.textures = append(this.textures, newPageTexture())
+		}
+		this.textures[p].Bind(gl.TEXTURE_2D)
+		//mipmaps read from a KTX2 file don't survive the page changing size
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, 0)
+		if this.compression != CompressNone {
+			this.uploadCompressedPage(p)
+			continue
+		}
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, img.Bounds().Dx(), img.Bounds().Dy(), 0, gl.RGBA, gl.UNSIGNED_BYTE, img.Pix)
+	}
+	this.packQuads()
+}
+
+//packQuads refreshes the font's copy of the atlas quads, which batches are built from
+func (this *Font) packQuads() {
+	this.quads = packVertices(this.atlas.coords)
+	if this.storage != nil {
+		this.storage.uploadQuads(this.atlas.coords)
+		this.stats.BytesUploaded += 16 * len(this.atlas.coords)
+	}
+}
+
+//glyphVertex is a corner of a glyph quad as the GPU sees it: the position as normalized shorts and the texture coordinates
+//as normalized unsigned shorts, half the size of the Vector4 the atlas keeps
+type glyphVertex struct {
+	x, y int16
+	u, v uint16
+}
+
+func packVertices(coords []Vector4) []glyphVertex {
+	vertices := make([]glyphVertex, len(coords))
+	for i, c := range coords {
+		vertices[i] = glyphVertex{
+			int16(clampFloat(c[0], -1, 1) * math.MaxInt16),
+			int16(clampFloat(c[1], -1, 1) * math.MaxInt16),
+			uint16(clampFloat(c[2], 0, 1)*math.MaxUint16 + 0.5),
+			uint16(clampFloat(c[3], 0, 1)*math.MaxUint16 + 0.5),
+		}
+	}
+	return vertices
+}
+
+func clampFloat(v, lo, hi float32) float32 {
+	return max32(lo, min32(v, hi))
+}
+
+//uploadCell copies a single glyph's cell to its page's texture
+func (this *Font) uploadCell(i int) {
+	defer endTrace(beginTrace(PhaseUpload))
+	a := this.atlas
+	if this.compression != CompressNone {
+		this.uploadCompressedCell(i)
+		return
+	}
+	page, origin := a.cell(i)
+	//rows of the cell are not contiguous in the atlas, so pack them for upload
+	pix := make([]uint8, 0, a.cellWidth*a.cellPixels*4)
+	for y := 0; y < a.cellPixels; y++ {
+		row := page.PixOffset(origin.X, origin.Y+y)
+		pix = append(pix, page.Pix[row:row+a.cellWidth*4]...)
+	}
+	this.stats.BytesUploaded += len(pix)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	if this.dsa {
+		gl.TextureSubImage2D(this.textures[a.page(i)], 0, origin.X, origin.Y, a.cellWidth, a.cellPixels, gl.RGBA, gl.UNSIGNED_BYTE, pix)
+		return
+	}
+	this.textures[a.page(i)].Bind(gl.TEXTURE_2D)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, origin.X, origin.Y, a.cellWidth, a.cellPixels, gl.RGBA, gl.UNSIGNED_BYTE, pix)
+}
@@ -0,0 +1,62 @@
+package gltext
+
+import (
+	"image"
+	"image/draw"
+)
+
+//AtlasGlyph describes one baked glyph, for tools such as editors and exporters built on the font's atlas
+type AtlasGlyph struct {
+	Rune    rune
+	Index   int             //the Index reported by EachGlyph for this rune
+	Page    int             //which of AtlasPages the glyph is on
+	Cell    image.Rectangle //where the glyph is on its page, in pixels
+	UV      Vector4         //texture coordinates of the cell's top left and bottom right corners
+	Advance float32         //in draw units
+	Ink     Rect            //tight bounds relative to the top left of the glyph's quad, in draw units
+	Dynamic bool            //loaded on demand and liable to be evicted
+}
+
+//AtlasPages returns a copy of every atlas page, as uploaded to the GPU
+func (this *Font) AtlasPages() []*image.RGBA {
+	pages := make([]*image.RGBA, len(this.atlas.pages))
+	for p, page := range this.atlas.pages {
+		pages[p] = image.NewRGBA(page.Bounds())
+		draw.Draw(pages[p], page.Bounds(), page, image.ZP, draw.Src)
+	}
+	return pages
+}
+
+//AtlasGlyphs lists every glyph in the atlas in index order. it describes the atlas as of AtlasGeneration.
+func (this *Font) AtlasGlyphs() []AtlasGlyph {
+	glyphs := make([]AtlasGlyph, len(this.atlas.offsets))
+	for i := range glyphs {
+		glyphs[i] = this.atlasGlyph(i)
+	}
+	return glyphs
+}
+
+//AtlasGlyph describes the glyph baked for r, if there is one. unlike drawing, it never loads glyphs on demand.
+func (this *Font) AtlasGlyph(r rune) (AtlasGlyph, bool) {
+	i, ok := this.atlas.runes[r]
+	if !ok {
+		return AtlasGlyph{}, false
+	}
+	return this.atlasGlyph(i), true
+}
+
+func (this *Font) atlasGlyph(i int) AtlasGlyph {
+	a := this.atlas
+	page, origin := a.cell(i)
+	quad := a.coords[i*4 : i*4+4]
+	return AtlasGlyph{
+		Rune:    a.glyphs[i],
+		Index:   i,
+		Page:    a.page(i),
+		Cell:    image.Rect(0, 0, a.cellWidth, a.cellPixels).Add(origin).Intersect(page.Bounds()),
+		UV:      Vector4{quad[0][2], quad[0][3], quad[3][2], quad[3][3]},
+		Advance: a.offsets[i],
+		Ink:     a.ink[i],
+		Dynamic: a.evictable[i],
+	}
+}
@@ -0,0 +1,299 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"math"
+	"reflect"
+)
+
+//batchVertex is a corner of a glyph quad as drawn: the corner of the atlas quad, with where and how its glyph is placed.
+//all the glyphs of a layout go into one vertex buffer, so that each pass over it takes a handful of indexed draws.
+type batchVertex struct {
+	glyphVertex
+	x, y         float32 //where the glyph's quad is drawn from
+	scale, angle float32
+	glyph, atlas float32 //index of the glyph in its layout, for animations, and in the atlas
+	colorIndex   float32 //the entry of the font's indexed colors the glyph is drawn in, with Style.IndexedColor
+}
+
+//glyphRun is a span of the batch that can be drawn in one call: its glyphs share an atlas page and a color
+type glyphRun struct {
+	first, count int //in glyphs
+	page         int
+	color        *Vector4 //the glyphs' own color, or nil to draw them in the pass's color
+}
+
+//glyphAttribs are the vertex attributes of the glyph program, bound to the locations of their indices
+//so that every program built for a font can share its vertex array
+var glyphAttribs = []string{"position", "texcoord", "placement", "glyphIndex", "drawIndex"}
+
+//setupVertices points the glyph attributes at the batch, in vbo or the vertex stream, and binds the quad indices
+func (this *Font) setupVertices() []gl.AttribLocation {
+	vertices := this.vbo
+	if this.stream != nil {
+		vertices = this.stream.buffer
+	}
+	vertices.Bind(gl.ARRAY_BUFFER)
+	attribs := setVertexAttribs()
+	vertices.Unbind(gl.ARRAY_BUFFER)
+	this.ebo.Bind(gl.ELEMENT_ARRAY_BUFFER)
+	return attribs
+}
+
+//setVertexAttribs describes batchVertex to the bound vertex array
+func setVertexAttribs() []gl.AttribLocation {
+	t := reflect.TypeOf(batchVertex{})
+	stride := int(t.Size())
+	corner := reflect.TypeOf(glyphVertex{})
+	position, texcoord, placement, index := gl.AttribLocation(0), gl.AttribLocation(1), gl.AttribLocation(2), gl.AttribLocation(3)
+	position.AttribPointer(2, gl.SHORT, true, stride, nil)
+	position.EnableArray()
+	texcoord.AttribPointer(2, gl.UNSIGNED_SHORT, true, stride, uintptr(corner.Field(2).Offset))
+	texcoord.EnableArray()
+	placement.AttribPointer(4, gl.FLOAT, false, stride, uintptr(t.Field(1).Offset))
+	placement.EnableArray()
+	index.AttribPointer(3, gl.FLOAT, false, stride, uintptr(t.Field(5).Offset))
+	index.EnableArray()
+	return []gl.AttribLocation{position, texcoord, placement, index}
+}
+
+func (this *Font) beginBatch() {
+	this.batch, this.runs, this.runStart = this.batch[:0], this.runs[:0], 0
+	if this.storage != nil {
+		this.storage.records = this.storage.records[:0]
+	}
+	if this.points != nil {
+		this.points.points = this.points.points[:0]
+	}
+}
+
+//batchLen is the number of glyphs in the batch
+func (this *Font) batchLen() int {
+	switch this.path {
+	case GlyphStorage:
+		return len(this.storage.records)
+	case GlyphPoints:
+		return len(this.points.points)
+	}
+	return len(this.batch) / 4
+}
+
+//batchGlyph adds glyph i of a layout, drawn from atlas glyph index with its quad's top left at x, y
+func (this *Font) batchGlyph(i, index int, x, y, scale, angle float32, color *Vector4, colorIndex int) {
+	this.stats.Glyphs++
+	//text drawn from cached layouts isn't shaped again, so a glyph counts as used when it is drawn
+	this.atlas.touch(index)
+	page := this.atlas.page(index)
+	if last := len(this.runs) - 1; last >= this.runStart && this.runs[last].page == page && sameColor(this.runs[last].color, color) {
+		this.runs[last].count++
+	} else {
+		this.runs = append(this.runs, glyphRun{this.batchLen(), 1, page, color})
+	}
+	switch this.path {
+	case GlyphStorage:
+		this.storage.records = append(this.storage.records, glyphRecord{x, y, scale, angle, float32(i), float32(index), float32(colorIndex), 0})
+		return
+	case GlyphPoints:
+		this.points.add(this.quads[index*4], this.quads[index*4+3], i, index, colorIndex, x, y, scale, angle)
+		return
+	}
+	for _, corner := range this.quads[index*4 : index*4+4] {
+		this.batch = append(this.batch, batchVertex{corner, x, y, scale, angle, float32(i), float32(index), float32(colorIndex)})
+	}
+}
+
+func sameColor(a, b *Vector4) bool {
+	return a == b || a != nil && b != nil && *a == *b
+}
+
+//uploadBatch streams the batch to the GPU, making sure there are indices enough to draw it.
+//it must be called before the font's vertex array is bound.
+func (this *Font) uploadBatch() {
+	quads := this.batchLen()
+	if quads == 0 {
+		return
+	}
+	defer endTrace(beginTrace(PhaseUpload))
+	this.stats.BytesUploaded += this.batchBytes()
+	switch this.path {
+	case GlyphStorage:
+		this.storage.upload()
+	case GlyphPoints:
+		//points are drawn without indices
+		this.points.upload()
+		return
+	default:
+		if this.stream != nil {
+			this.streamBatch()
+			break
+		}
+		//respecifying the buffer lets the driver hand over fresh storage rather than wait for draws still reading the old
+		bufferData(this.dsa, this.vbo, gl.ARRAY_BUFFER, int(reflect.TypeOf(batchVertex{}).Size())*len(this.batch), &this.batch[0], gl.STREAM_DRAW)
+	}
+	if quads > this.indexQuads {
+		this.growIndices(quads)
+	}
+}
+
+//growIndices fills the index buffer with two triangles for each of at least n quads
+func (this *Font) growIndices(n int) {
+	n = maxInt(maxInt(n, 2*this.indexQuads), 256)
+	indices := make([]uint32, 0, 6*n)
+	for q := uint32(0); q < uint32(n); q++ {
+		v := 4 * q
+		indices = append(indices, v, v+1, v+2, v+2, v+1, v+3)
+	}
+	this.stats.BytesUploaded += 4 * len(indices)
+	if this.dsa {
+		gl.NamedBufferData(this.ebo, 4*len(indices), indices, gl.STATIC_DRAW)
+	} else {
+		//the element buffer binding belongs to the vertex array
+		this.vao.Bind()
+		this.ebo.Bind(gl.ELEMENT_ARRAY_BUFFER)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, 4*len(indices), indices, gl.STATIC_DRAW)
+		this.vao.Unbind()
+	}
+	this.indexQuads = n
+}
+
+//glyphDraw is one indexed draw of a flush
+type glyphDraw struct {
+	params       int //which DrawParams in the uniform buffer
+	first, count int //in glyphs
+	page         int //or -1 for the halo field
+}
+
+//drawCalls counts the program and texture binds and the draw calls a font makes, rectangles included
+type drawCalls struct {
+	programs, textures, draws int
+}
+
+func (this drawCalls) sub(from drawCalls) drawCalls {
+	return drawCalls{this.programs - from.programs, this.textures - from.textures, this.draws - from.draws}
+}
+
+//drawParamsBinding is the uniform buffer binding point DrawParams is read from
+const drawParamsBinding = 0
+
+//drawParamsSize is the size of the DrawParams block in bytes: two vec4s, a vec2 and an int, padded to a vec4 under std140
+const drawParamsSize = 48
+
+//drawParamsStride returns how many floats apart DrawParams records must be to satisfy the driver's alignment of uniform buffer ranges
+func drawParamsStride() int {
+	align := make([]int32, 1)
+	gl.GetIntegerv(gl.UNIFORM_BUFFER_OFFSET_ALIGNMENT, align)
+	a := maxInt(int(align[0]), 16)
+	return (drawParamsSize + a - 1) / a * a / 4
+}
+
+//addParams appends a DrawParams record for the next flush and returns its index
+func (this *Font) addParams(color Vector4, dx, dy float32, effects Effect, a Animation) int {
+	start := len(this.params)
+	this.params = append(this.params, color[0], color[1], color[2], color[3], a.Time, a.Amplitude, a.Speed, a.Spread,
+		dx, dy, math.Float32frombits(uint32(effects)))
+	for len(this.params) < start+this.paramStride {
+		this.params = append(this.params, 0)
+	}
+	return start / this.paramStride
+}
+
+//splitRuns keeps the glyphs batched next from joining the runs already batched, so that they can be queued apart
+func (this *Font) splitRuns() {
+	this.runStart = len(this.runs)
+}
+
+//queueGlyphs adds a pass over runs of the uploaded batch in color, moved by dx, dy, to the next flush. the atlas pages are drawn
+//from unless halo is set. with perGlyph set, glyphs with a color of their own are drawn in it, its opacity scaled by alpha.
+//neighbouring runs that differ only in what the pass ignores are drawn together.
+func (this *Font) queueGlyphs(runs []glyphRun, color Vector4, dx, dy float32, effects Effect, animation Animation, perGlyph, halo bool, alpha float32) {
+	params := this.addParams(color, dx, dy, effects, animation)
+	for r := 0; r < len(runs); {
+		run := runs[r]
+		end := r + 1
+		for end < len(runs) && (halo || runs[end].page == run.page) && (!perGlyph || sameColor(runs[end].color, run.color)) {
+			end++
+		}
+		draw := glyphDraw{params, run.first, 0, run.page}
+		if perGlyph && run.color != nil {
+			want := *run.color
+			want[3] *= alpha
+			draw.params = this.addParams(want, dx, dy, effects, animation)
+		}
+		if halo {
+			draw.page = -1
+		}
+		last := runs[end-1]
+		draw.count = last.first + last.count - run.first
+		this.draws = append(this.draws, draw)
+		r = end
+	}
+}
+
+//flushGlyphs uploads the DrawParams of every queued pass at once, then draws them in order
+func (this *Font) flushGlyphs() {
+	if len(this.draws) == 0 {
+		this.params = this.params[:0]
+		return
+	}
+	defer endTrace(beginTrace(PhaseDraw))
+	target := gl.GLenum(gl.UNIFORM_BUFFER)
+	if this.path == GlyphStorage {
+		target = gl.SHADER_STORAGE_BUFFER
+	}
+	bufferData(this.dsa, this.ubo, target, 4*len(this.params), &this.params[0], gl.STREAM_DRAW)
+	this.stats.BytesUploaded += 4 * len(this.params)
+
+	blend()
+	this.program.Use()
+	this.calls.programs++
+	gl.ActiveTexture(gl.TEXTURE0)
+	this.bound = -2
+	switch this.path {
+	case GlyphStorage:
+		this.storage.bind()
+		this.ubo.BindBufferBase(gl.SHADER_STORAGE_BUFFER, paramsStorageBinding)
+		this.storage.drawIndirect(this)
+	case GlyphPoints:
+		this.points.vao.Bind()
+		this.drawEach()
+	default:
+		this.vao.Bind()
+		this.drawEach()
+	}
+	this.vao.Unbind() //unbinds whichever vertex array is bound
+	this.program.Unuse()
+	gl.Disable(gl.BLEND)
+	this.params, this.draws = this.params[:0], this.draws[:0]
+}
+
+//drawEach draws the queued passes one call at a time, binding each its range of the uniform buffer
+func (this *Font) drawEach() {
+	params := -1
+	for _, d := range this.draws {
+		if d.params != params {
+			this.ubo.BindBufferRange(gl.UNIFORM_BUFFER, drawParamsBinding, 4*d.params*this.paramStride, drawParamsSize)
+			params = d.params
+		}
+		this.bindDrawTexture(d.page)
+		this.calls.draws++
+		if this.path == GlyphPoints {
+			gl.DrawArrays(gl.POINTS, d.first, d.count)
+		} else {
+			gl.DrawElementsBaseVertex(gl.TRIANGLES, 6*d.count, gl.UNSIGNED_INT, uintptr(6*4*d.first), this.baseVertex)
+		}
+	}
+}
+
+//bindDrawTexture binds the atlas page a draw reads from, or the halo field for page -1, unless it is already bound
+func (this *Font) bindDrawTexture(page int) {
+	if page == this.bound {
+		return
+	}
+	if page < 0 {
+		this.halo.texture.Bind(gl.TEXTURE_2D)
+	} else {
+		this.textures[page].Bind(gl.TEXTURE_2D)
+	}
+	this.bound = page
+	this.calls.textures++
+}
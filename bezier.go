@@ -0,0 +1,54 @@
+package gltext
+
+import "math"
+
+//Bezier is a cubic Bézier segment in draw units, from P0 to P3 with control points P1 and P2
+type Bezier struct {
+	P0, P1, P2, P3 [2]float32
+}
+
+func (this Bezier) point(t float32) [2]float32 {
+	u := 1 - t
+	a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+	return [2]float32{
+		a*this.P0[0] + b*this.P1[0] + c*this.P2[0] + d*this.P3[0],
+		a*this.P0[1] + b*this.P1[1] + c*this.P2[1] + d*this.P3[1],
+	}
+}
+
+//BezierPath flattens curves, which should join end to end, into a path fine enough that glyphs spaced along it
+//by distance are evenly spread however unevenly each curve's parameter runs
+func (this *Font) BezierPath(curves ...Bezier) Path {
+	path := make(Path, 0)
+	for i, c := range curves {
+		steps := this.bezierSteps(c)
+		for s := 0; s <= steps; s++ {
+			if s == 0 && i > 0 {
+				continue
+			}
+			path = append(path, c.point(float32(s)/float32(steps)))
+		}
+	}
+	return path
+}
+
+//bezierSteps flattens a curve into segments of roughly four pixels, going by the length of its control polygon
+func (this *Font) bezierSteps(c Bezier) int {
+	aspect := float64(this.aspect())
+	points := [][2]float32{c.P0, c.P1, c.P2, c.P3}
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		dx, dy := float64(points[i][0]-points[i-1][0])*aspect, float64(points[i][1]-points[i-1][1])
+		length += math.Hypot(dx, dy)
+	}
+	steps := int(length / float64(4*this.atlas.sy))
+	if steps < 8 {
+		steps = 8
+	}
+	return steps
+}
+
+//LayoutBezier lays s out along curves the way LayoutPath does
+func (this *Font) LayoutBezier(curves []Bezier, offset float32, style Style, s string) *Layout {
+	return this.LayoutPath(this.BezierPath(curves...), offset, style, s)
+}
@@ -0,0 +1,88 @@
+package gltext
+
+//BufferLayout lays out a TextBuffer a line at a time, one cell height apart, and after edits lays out again only the
+//lines they touched, moving the rest. it must be the only BufferLayout of its buffer.
+type BufferLayout struct {
+	Style Style
+	//Highlight, if set, returns the spans of each line as it is laid out again, so only edited lines are highlighted again
+	Highlight func(line int, text string) []Span
+
+	font   *Font
+	buffer *TextBuffer
+	x, y   float32
+	lines  []*Layout
+}
+
+func NewBufferLayout(font *Font, buffer *TextBuffer, x, y float32, style Style) *BufferLayout {
+	this := &BufferLayout{Style: style, font: font, buffer: buffer, x: x, y: y}
+	this.Invalidate()
+	return this
+}
+
+//Invalidate lays out every line again at the next Update, as after changing Style
+func (this *BufferLayout) Invalidate() {
+	this.buffer.takeDamage()
+	this.lines = this.lines[:0]
+	this.buffer.damage = lineDamage{0, this.buffer.LineCount(), this.buffer.LineCount(), true}
+}
+
+//Update brings the layouts up to date with the buffer
+func (this *BufferLayout) Update() {
+	d := this.buffer.takeDamage()
+	if !d.any {
+		return
+	}
+	fresh := make([]*Layout, 0, d.end-d.first)
+	for i := d.first; i < d.end; i++ {
+		start, end := this.buffer.Line(i)
+		text := string(this.buffer.Slice(start, end))
+		layout := this.font.LayoutStyle(this.x, this.lineY(i), this.Style, text)
+		if this.Highlight != nil {
+			layout.SetSpans(this.Highlight(i, text))
+		}
+		fresh = append(fresh, layout)
+	}
+	after := this.lines[minInt(d.end-d.delta, len(this.lines)):]
+	this.lines = append(append(this.lines[:d.first:d.first], fresh...), after...)
+	if d.delta != 0 {
+		for i := d.end; i < len(this.lines); i++ {
+			this.lines[i].MoveTo(this.x, this.lineY(i))
+		}
+	}
+}
+
+func (this *BufferLayout) lineY(i int) float32 {
+	return this.y - float32(i)*this.font.cellHeight
+}
+
+//Lines returns the layout of each line of the buffer, as of the last Update
+func (this *BufferLayout) Lines() []*Layout {
+	return this.lines
+}
+
+//MoveTo moves every line so that the first starts at x, y
+func (this *BufferLayout) MoveTo(x, y float32) {
+	this.x, this.y = x, y
+	for i, l := range this.lines {
+		l.MoveTo(x, this.lineY(i))
+	}
+}
+
+//Draw updates the layouts and draws them together, each with its own alpha
+func (this *BufferLayout) Draw() {
+	this.Update()
+	layouts := make([]*Layout, 0, len(this.lines))
+	var styles []Style
+	var alphas []float32
+	for _, l := range this.lines {
+		if l.alpha <= 0 {
+			continue
+		}
+		style := l.style
+		if l.alpha != 1 {
+			style = style.withAlpha(l.alpha)
+		}
+		layouts, styles, alphas = append(layouts, l), append(styles, style), append(alphas, l.alpha)
+	}
+	this.font.drawLayouts(layouts, styles, alphas)
+}
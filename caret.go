@@ -0,0 +1,42 @@
+package gltext
+
+import (
+	"time"
+)
+
+//CaretWidth is how wide carets are drawn, in pixels
+var CaretWidth float32 = 1
+
+//DrawCaret draws the insertion point before the rune at index, as CaretPos places it: a bar CaretWidth wide and the
+//height of its line
+func (this *Layout) DrawCaret(index int, color Vector4) {
+	x, y, height := this.CaretPos(index)
+	this.font.fillRect(x, y, CaretWidth*2/this.font.source.width, height, color)
+}
+
+//CaretBlink times a blinking caret, shown for the first half of each Period and hidden for the second. a Period of
+//zero never hides it.
+type CaretBlink struct {
+	Period time.Duration
+
+	elapsed time.Duration
+}
+
+//DefaultCaretBlink is the period of most desktop platforms
+const DefaultCaretBlink = 1060 * time.Millisecond
+
+func (this *CaretBlink) Update(dt time.Duration) {
+	this.elapsed += dt
+}
+
+//Reset shows the caret and starts the period again, as when it moves or text is typed
+func (this *CaretBlink) Reset() {
+	this.elapsed = 0
+}
+
+func (this *CaretBlink) Visible() bool {
+	if this.Period <= 0 {
+		return true
+	}
+	return this.elapsed%this.Period < this.Period/2
+}
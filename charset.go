@@ -0,0 +1,90 @@
+package gltext
+
+//Charset is a named bundle of rune ranges to bake, for passing to NewFontRanges as Ranges...
+type Charset struct {
+	Name   string
+	Ranges []RuneRange
+}
+
+var (
+	Latin1 = Charset{"Latin-1", []RuneRange{{0x20, 0x7E}, {0xA0, 0xFF}}}
+	//LatinExtended is Latin Extended-A and B, for the rest of the European languages written in Latin script
+	LatinExtended = Charset{"Latin Extended", []RuneRange{{0x100, 0x24F}}}
+	Greek         = Charset{"Greek", []RuneRange{{0x370, 0x3FF}}}
+	Cyrillic      = Charset{"Cyrillic", []RuneRange{{0x400, 0x4FF}}}
+	//Vietnamese is the letters Vietnamese adds to Latin-1, along with its combining tone marks and the dong sign
+	Vietnamese = Charset{"Vietnamese", []RuneRange{
+		{0x102, 0x103}, {0x110, 0x111}, {0x128, 0x129}, {0x168, 0x169}, {0x1A0, 0x1A1}, {0x1AF, 0x1B0},
+		{0x300, 0x301}, {0x303, 0x303}, {0x309, 0x309}, {0x323, 0x323}, {0x1EA0, 0x1EF9}, {0x20AB, 0x20AB},
+	}}
+	//BoxDrawing has the box drawing characters and block elements of text mode interfaces
+	BoxDrawing = Charset{"Box Drawing", []RuneRange{{0x2500, 0x259F}}}
+)
+
+//Charsets are the presets in the order Coverage prefers them
+var Charsets = []Charset{Latin1, LatinExtended, Greek, Cyrillic, Vietnamese, BoxDrawing}
+
+func (this Charset) Contains(r rune) bool {
+	for _, rr := range this.Ranges {
+		if r >= rr.Low && r <= rr.High {
+			return true
+		}
+	}
+	return false
+}
+
+//Coverage is what CheckCoverage found in a sample of text
+type Coverage struct {
+	Charsets  []Charset //the presets that together hold every rune of the sample they can
+	Uncovered []rune    //runes in none of the presets
+	Missing   []rune    //runes the font has no glyph for
+}
+
+//Ranges returns the ranges of all the charsets needed, for NewFontRanges, with a range of its own for each uncovered rune
+func (this Coverage) Ranges() []RuneRange {
+	ranges := make([]RuneRange, 0)
+	for _, c := range this.Charsets {
+		ranges = append(ranges, c.Ranges...)
+	}
+	for _, r := range this.Uncovered {
+		ranges = append(ranges, RuneRange{r, r})
+	}
+	return ranges
+}
+
+//CheckCoverage scans sample text, such as a language's localized strings, and reports which of Charsets are needed
+//to draw it and which of its runes the font at fontPath lacks. control characters such as newlines are ignored.
+func CheckCoverage(fontPath string, sample string) (Coverage, error) {
+	font, err := parseFontFile(fontPath)
+	if err != nil {
+		return Coverage{}, err
+	}
+	var coverage Coverage
+	needed := make([]bool, len(Charsets))
+	seen := make(map[rune]bool)
+	for _, ch := range sample {
+		if seen[ch] || ch < 0x20 {
+			continue
+		}
+		seen[ch] = true
+		if font.Index(ch) == 0 {
+			coverage.Missing = append(coverage.Missing, ch)
+		}
+		covered := false
+		for i, c := range Charsets {
+			if c.Contains(ch) {
+				needed[i], covered = true, true
+				break
+			}
+		}
+		if !covered {
+			coverage.Uncovered = append(coverage.Uncovered, ch)
+		}
+	}
+	for i, c := range Charsets {
+		if needed[i] {
+			coverage.Charsets = append(coverage.Charsets, c)
+		}
+	}
+	return coverage, nil
+}
@@ -0,0 +1,69 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+)
+
+//pixelRect converts a draw space rectangle into window pixels with the origin at the bottom left, as glScissor expects.
+//it assumes the viewport matches the width and height the font was created with.
+func (this *Font) pixelRect(r Rect) (x, y, w, h int) {
+	sw, sh := this.source.width, this.source.height
+	left := r.X * sw / 2
+	top := -r.Y * sh / 2
+	right := (r.X + r.W) * sw / 2
+	bottom := (-r.Y + r.H) * sh / 2
+	return int(left), int(sh - bottom), int(right - left + 0.5), int(bottom - top + 0.5)
+}
+
+//scissor restricts drawing to r until the returned function is called, which restores the previous scissor state
+func (this *Font) scissor(r Rect) func() {
+	wasEnabled := gl.IsEnabled(gl.SCISSOR_TEST)
+	box := make([]int32, 4)
+	gl.GetIntegerv(gl.SCISSOR_BOX, box)
+
+	x, y, w, h := this.pixelRect(r)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(x, y, w, h)
+
+	return func() {
+		gl.Scissor(int(box[0]), int(box[1]), int(box[2]), int(box[3]))
+		if !wasEnabled {
+			gl.Disable(gl.SCISSOR_TEST)
+		}
+	}
+}
+
+//SetClipRect restricts everything this font draws to the rectangle whose top left corner is x, y
+func (this *Font) SetClipRect(x, y, w, h float32) {
+	this.clip = &Rect{x, y, w, h}
+}
+
+func (this *Font) ClearClipRect() {
+	this.clip = nil
+}
+
+//ClipRect returns the current clip rectangle, if any
+func (this *Font) ClipRect() (Rect, bool) {
+	if this.clip == nil {
+		return Rect{}, false
+	}
+	return *this.clip, true
+}
+
+//withClip runs draw with the clip rectangle narrowed to r
+func (this *Font) withClip(r Rect, draw func()) {
+	previous := this.clip
+	if previous != nil {
+		r = r.Intersect(*previous)
+	}
+	this.clip = &r
+	defer func() { this.clip = previous }()
+	draw()
+}
+
+func (this *Font) applyClip() func() {
+	if this.clip == nil {
+		return func() {}
+	}
+	return this.scissor(*this.clip)
+}
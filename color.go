@@ -0,0 +1,84 @@
+package gltext
+
+import (
+	"strconv"
+	"strings"
+)
+
+//RGBA makes a color from 8 bit channels
+func RGBA(r, g, b, a uint8) Vector4 {
+	return Vector4{float32(r) / 255, float32(g) / 255, float32(b) / 255, float32(a) / 255}
+}
+
+//SetColorRGBA sets the color of the style used by Printf from 8 bit channels
+func (this *Font) SetColorRGBA(r, g, b, a uint8) {
+	this.style.Color = RGBA(r, g, b, a)
+}
+
+//NamedColors are the color names ParseColor knows besides hex
+var NamedColors = map[string]Vector4{
+	"transparent": {0, 0, 0, 0},
+	"black":       {0, 0, 0, 1},
+	"white":       {1, 1, 1, 1},
+	"gray":        {0.5, 0.5, 0.5, 1},
+	"red":         {1, 0, 0, 1},
+	"green":       {0, 1, 0, 1},
+	"blue":        {0, 0, 1, 1},
+	"yellow":      {1, 1, 0, 1},
+	"cyan":        {0, 1, 1, 1},
+	"magenta":     {1, 0, 1, 1},
+	"orange":      {1, 0.65, 0, 1},
+}
+
+//ParseColor reads a name from NamedColors, in any case, or a hex color as "#rgb", "#rgba", "#rrggbb" or "#rrggbbaa".
+//it returns a *ColorSyntaxError for anything else.
+func ParseColor(s string) (Vector4, error) {
+	if c, ok := NamedColors[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == len(s) {
+		return Vector4{}, &ColorSyntaxError{s}
+	}
+	if len(hex) == 3 || len(hex) == 4 {
+		//each digit stands for two
+		long := make([]byte, 0, 8)
+		for i := 0; i < len(hex); i++ {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return Vector4{}, &ColorSyntaxError{s}
+	}
+	return RGBA(uint8(v>>24), uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
+
+//Palette names the colors of a UI theme. styles refer to its entries by PaletteColor, so swapping the font's palette
+//recolors all of them at once.
+type Palette map[string]Vector4
+
+//SetPalette replaces the font's palette
+func (this *Font) SetPalette(p Palette) {
+	this.palette = p
+}
+
+func (this *Font) Palette() Palette {
+	return this.palette
+}
+
+//themed returns style with its color taken from the palette, if it names a palette color, at the opacity alpha
+func (this *Font) themed(style Style, alpha float32) Style {
+	if style.PaletteColor == "" {
+		return style
+	}
+	if c, ok := this.palette[style.PaletteColor]; ok {
+		style.Color = c
+		style.Color[3] *= alpha
+	}
+	return style
+}
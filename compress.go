@@ -0,0 +1,293 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"image"
+)
+
+//AtlasCompression is how atlas pages are stored on the GPU. compressed pages take a quarter of the memory but keep only
+//each pixel's coverage, so custom glyphs added with AddGlyph draw as silhouettes in the text color.
+type AtlasCompression int
+
+const (
+	CompressNone  AtlasCompression = iota
+	CompressBC4                    //RGTC1, part of OpenGL since 3.0
+	CompressETC2R                  //EAC R11, part of OpenGL since 4.3
+)
+
+//SetAtlasCompression re-uploads the atlas in the given format; glyphs loaded or added later are compressed as they arrive.
+//it returns ErrCompressionUnsupported, leaving the atlas as it was, if the driver cannot sample the format.
+func (this *Font) SetAtlasCompression(c AtlasCompression) error {
+	if c == CompressETC2R && !glVersionAtLeast(4, 3) {
+		return ErrCompressionUnsupported
+	}
+	this.setCompression(c)
+	this.uploadAtlas()
+	return nil
+}
+
+//setCompression tells the shader how to read the atlas, without uploading it
+func (this *Font) setCompression(c AtlasCompression) {
+	this.compression = c
+	single := 0
+	if c != CompressNone {
+		single = 1
+	}
+	this.program.Use()
+	this.program.GetUniformLocation("singleChannel").Uniform1i(single)
+	this.program.Unuse()
+}
+
+func (this *Font) AtlasCompression() AtlasCompression {
+	return this.compression
+}
+
+func (this AtlasCompression) internalFormat() gl.GLenum {
+	if this == CompressETC2R {
+		return gl.COMPRESSED_R11_EAC
+	}
+	return gl.COMPRESSED_RED_RGTC1
+}
+
+func glVersionAtLeast(major, minor int) bool {
+	version := make([]int32, 2)
+	gl.GetIntegerv(gl.MAJOR_VERSION, version[:1])
+	gl.GetIntegerv(gl.MINOR_VERSION, version[1:])
+	return int(version[0]) > major || int(version[0]) == major && int(version[1]) >= minor
+}
+
+//uploadCompressedPage replaces the bound texture with page p compressed
+func (this *Font) uploadCompressedPage(p int) {
+	img := this.atlas.pages[p]
+	data := compressRegion(img, img.Bounds(), this.compression)
+	gl.CompressedTexImage2D(gl.TEXTURE_2D, 0, this.compression.internalFormat(), img.Bounds().Dx(), img.Bounds().Dy(), 0, len(data), data)
+}
+
+//uploadCompressedCell re-encodes the blocks covering glyph i's cell into its page's texture.
+//compressed uploads have to start on a block, so the cell is grown to the 4x4 blocks around it.
+func (this *Font) uploadCompressedCell(i int) {
+	a := this.atlas
+	page, origin := a.cell(i)
+	r := image.Rect(origin.X&^3, origin.Y&^3, (origin.X+a.cellWidth+3)&^3, (origin.Y+a.cellPixels+3)&^3).Intersect(page.Bounds())
+	data := compressRegion(page, r, this.compression)
+	this.stats.BytesUploaded += len(data)
+	if this.dsa {
+		gl.CompressedTextureSubImage2D(this.textures[a.page(i)], 0, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), this.compression.internalFormat(), len(data), data)
+		return
+	}
+	this.textures[a.page(i)].Bind(gl.TEXTURE_2D)
+	gl.CompressedTexSubImage2D(gl.TEXTURE_2D, 0, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), this.compression.internalFormat(), len(data), data)
+}
+
+//compressRegion encodes the alpha of r, which starts on a block, as 4x4 blocks of 8 bytes in rows from the top.
+//pixels past the edge of img are transparent.
+func compressRegion(img *image.RGBA, r image.Rectangle, c AtlasCompression) []byte {
+	data := make([]byte, 0, (r.Dx()+3)/4*((r.Dy()+3)/4)*8)
+	//most blocks are empty or solid, so remember what those encode to
+	constant := make(map[uint8][]byte)
+	var block [16]uint8
+	for by := r.Min.Y; by < r.Max.Y; by += 4 {
+		for bx := r.Min.X; bx < r.Max.X; bx += 4 {
+			lo, hi := uint8(255), uint8(0)
+			for i := range block {
+				p := image.Pt(bx+i%4, by+i/4)
+				block[i] = 0
+				if p.In(img.Bounds()) {
+					block[i] = img.Pix[img.PixOffset(p.X, p.Y)+3]
+				}
+				lo, hi = minUint8(lo, block[i]), maxUint8(hi, block[i])
+			}
+			if encoded, ok := constant[lo]; ok && lo == hi {
+				data = append(data, encoded...)
+				continue
+			}
+			start := len(data)
+			if c == CompressETC2R {
+				data = encodeEAC(&block, lo, hi, data)
+			} else {
+				data = encodeBC4(&block, lo, hi, data)
+			}
+			if lo == hi {
+				constant[lo] = data[start:len(data):len(data)]
+			}
+		}
+	}
+	return data
+}
+
+//encodeBC4 appends a BC4 block: the two endpoints, then a 3 bit palette index for each pixel in rows, least significant first
+func encodeBC4(block *[16]uint8, lo, hi uint8, data []byte) []byte {
+	if lo == hi {
+		return append(data, hi, lo, 0, 0, 0, 0, 0, 0)
+	}
+	//with the first endpoint larger, the palette is hi, lo and then six steps from hi towards lo
+	var bits uint64
+	span := int(hi) - int(lo)
+	for i, v := range block {
+		step := ((int(hi)-int(v))*7 + span/2) / span
+		index := step + 1
+		if step == 0 {
+			index = 0
+		} else if step == 7 {
+			index = 1
+		}
+		bits |= uint64(index) << uint(3*i)
+	}
+	data = append(data, hi, lo)
+	for i := uint(0); i < 6; i++ {
+		data = append(data, byte(bits>>(8*i)))
+	}
+	return data
+}
+
+//eacModifiers are the offsets each EAC table adds to a block's base value, before multiplying
+var eacModifiers = [16][8]int{
+	{-3, -6, -9, -15, 2, 5, 8, 14},
+	{-3, -7, -10, -13, 2, 6, 9, 12},
+	{-2, -5, -8, -13, 1, 4, 7, 12},
+	{-2, -4, -6, -13, 1, 3, 5, 12},
+	{-3, -6, -8, -12, 2, 5, 7, 11},
+	{-3, -7, -9, -11, 2, 6, 8, 10},
+	{-4, -7, -8, -11, 3, 6, 7, 10},
+	{-3, -5, -8, -11, 2, 4, 7, 10},
+	{-2, -6, -8, -10, 1, 5, 7, 9},
+	{-2, -5, -8, -10, 1, 4, 7, 9},
+	{-2, -4, -8, -10, 1, 3, 7, 9},
+	{-2, -5, -7, -10, 1, 4, 6, 9},
+	{-3, -4, -7, -10, 2, 3, 6, 9},
+	{-1, -2, -3, -10, 0, 1, 2, 9},
+	{-4, -6, -8, -9, 3, 5, 7, 8},
+	{-3, -5, -7, -9, 2, 4, 6, 8},
+}
+
+//encodeEAC appends an EAC R11 block, searching each table with the multipliers and base values that roughly fit the
+//block's range. the block is big endian: base, multiplier, table, then a 3 bit index for each pixel in columns.
+func encodeEAC(block *[16]uint8, lo, hi uint8, data []byte) []byte {
+	var target [16]int
+	for i, v := range block {
+		target[i] = int(v) * 2047 / 255
+	}
+	lo11, hi11 := int(lo)*2047/255, int(hi)*2047/255
+
+	bestErr, best := -1, uint64(0)
+	var indices [16]int
+	for table, mods := range eacModifiers {
+		span := mods[7] - mods[3]
+		first := (hi11 - lo11) / (span * 8)
+		for m := maxInt(first, 1); m <= first+1 && m <= 15; m++ {
+			centre := (hi11+lo11)/2 - (mods[7]+mods[3])*m*8/2
+			for base := (centre-4)/8 - 1; base <= (centre-4)/8+1; base++ {
+				if base < 0 || base > 255 {
+					continue
+				}
+				err := 0
+				for i, t := range target {
+					nearest := -1
+					for k, mod := range mods {
+						d := clampInt(base*8+4+mod*m*8, 0, 2047) - t
+						if d*d < nearest || nearest < 0 {
+							nearest, indices[i] = d*d, k
+						}
+					}
+					err += nearest
+				}
+				if err < bestErr || bestErr < 0 {
+					bestErr = err
+					best = uint64(base)<<56 | uint64(m)<<52 | uint64(table)<<48
+					for i, k := range indices {
+						x, y := i%4, i/4
+						best |= uint64(k) << uint(45-3*(x*4+y))
+					}
+				}
+			}
+		}
+	}
+	for i := uint(0); i < 8; i++ {
+		data = append(data, byte(best>>(56-8*i)))
+	}
+	return data
+}
+
+func clampInt(v, lo, hi int) int {
+	return maxInt(lo, minInt(v, hi))
+}
+
+func minUint8(a, b uint8) uint8 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint8(a, b uint8) uint8 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+//decompressRegion is the inverse of compressRegion, writing each pixel of r as white with the decoded coverage
+func decompressRegion(img *image.RGBA, r image.Rectangle, data []byte, c AtlasCompression) {
+	for by := r.Min.Y; by < r.Max.Y; by += 4 {
+		for bx := r.Min.X; bx < r.Max.X; bx += 4 {
+			if len(data) < 8 {
+				return
+			}
+			var block [16]uint8
+			if c == CompressETC2R {
+				block = decodeEAC(data[:8])
+			} else {
+				block = decodeBC4(data[:8])
+			}
+			data = data[8:]
+			for i, v := range block {
+				p := image.Pt(bx+i%4, by+i/4)
+				if p.In(img.Bounds()) {
+					o := img.PixOffset(p.X, p.Y)
+					img.Pix[o], img.Pix[o+1], img.Pix[o+2], img.Pix[o+3] = v, v, v, v
+				}
+			}
+		}
+	}
+}
+
+func decodeBC4(b []byte) (block [16]uint8) {
+	r0, r1 := int(b[0]), int(b[1])
+	palette := [8]int{r0, r1}
+	if r0 > r1 {
+		for k := 1; k < 7; k++ {
+			palette[k+1] = ((7-k)*r0 + k*r1) / 7
+		}
+	} else {
+		for k := 1; k < 5; k++ {
+			palette[k+1] = ((5-k)*r0 + k*r1) / 5
+		}
+		palette[6], palette[7] = 0, 255
+	}
+	var bits uint64
+	for i := uint(0); i < 6; i++ {
+		bits |= uint64(b[2+i]) << (8 * i)
+	}
+	for i := range block {
+		block[i] = uint8(palette[bits>>uint(3*i)&7])
+	}
+	return block
+}
+
+func decodeEAC(b []byte) (block [16]uint8) {
+	var bits uint64
+	for _, x := range b {
+		bits = bits<<8 | uint64(x)
+	}
+	base, m, mods := int(bits>>56), int(bits>>52&15), eacModifiers[bits>>48&15]
+	for i := range block {
+		x, y := i%4, i/4
+		mod := mods[bits>>uint(45-3*(x*4+y))&7]
+		v := base*8 + 4 + mod
+		if m != 0 {
+			v = base*8 + 4 + mod*m*8
+		}
+		block[i] = uint8((clampInt(v, 0, 2047)*255 + 1023) / 2047)
+	}
+	return block
+}
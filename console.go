@@ -0,0 +1,136 @@
+package gltext
+
+//Console is a scrollback log with an input row underneath, for in-game debug consoles.
+//Only the most recent capacity lines are kept.
+type Console struct {
+	X, Y     float32
+	Width    float32
+	Rows     int //number of scrollback lines shown above the input row
+	Style    Style
+	Input    *TextInput
+	Prompt   string
+	OnSubmit func(line string)
+	//AccessibleName is what screen readers announce the console as
+	AccessibleName string
+
+	font   *Font
+	lines  []string
+	head   int //index of the oldest line once the buffer is full
+	count  int
+	scroll int //lines scrolled back from the newest
+}
+
+func NewConsole(font *Font, x, y, width float32, rows, capacity int) *Console {
+	this := &Console{
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Rows:   rows,
+		Style:  font.style,
+		Prompt: "> ",
+		font:   font,
+		lines:  make([]string, capacity),
+	}
+	this.Input = NewTextInput(font, x, this.inputY(), width)
+	return this
+}
+
+func (this *Console) inputY() float32 {
+	return this.Y - float32(this.Rows)*this.font.cellHeight
+}
+
+//Printf appends formatted output to the scrollback, one entry per line
+func (this *Console) Printf(fs string, argv ...interface{}) {
+	for _, line := range splitLines(this.font.sprintf(fs, argv...)) {
+		this.append(line)
+	}
+}
+
+func (this *Console) append(line string) {
+	capacity := len(this.lines)
+	if capacity == 0 {
+		return
+	}
+	if this.count < capacity {
+		this.lines[(this.head+this.count)%capacity] = line
+		this.count++
+	} else {
+		this.lines[this.head] = line
+		this.head = (this.head + 1) % capacity
+	}
+	//keep the view still while the user is reading old output
+	if this.scroll > 0 {
+		this.scroll++
+	}
+	this.clampScroll()
+}
+
+//Line returns the i'th retained line, oldest first
+func (this *Console) Line(i int) string {
+	return this.lines[(this.head+i)%len(this.lines)]
+}
+
+func (this *Console) Len() int {
+	return this.count
+}
+
+func (this *Console) Clear() {
+	this.head, this.count, this.scroll = 0, 0, 0
+}
+
+func (this *Console) ScrollUp(lines int) {
+	this.scroll += lines
+	this.clampScroll()
+}
+
+func (this *Console) ScrollDown(lines int) {
+	this.scroll -= lines
+	this.clampScroll()
+}
+
+func (this *Console) ScrollToBottom() {
+	this.scroll = 0
+}
+
+func (this *Console) clampScroll() {
+	max := this.count - this.Rows
+	if this.scroll > max {
+		this.scroll = max
+	}
+	if this.scroll < 0 {
+		this.scroll = 0
+	}
+}
+
+//Submit echoes the input row into the scrollback, passes it to OnSubmit and clears it
+func (this *Console) Submit() {
+	line := this.Input.Text()
+	this.Input.SetText("")
+	this.Printf("%s%s", this.Prompt, line)
+	this.ScrollToBottom()
+	if this.OnSubmit != nil {
+		this.OnSubmit(line)
+	}
+}
+
+func (this *Console) Draw() {
+	last := this.count - this.scroll
+	first := last - this.Rows
+	if first < 0 {
+		first = 0
+	}
+	//the newest visible line sits directly above the input row
+	y := this.inputY() + float32(last-first)*this.font.cellHeight
+	for i := first; i < last; i++ {
+		this.font.LayoutStyle(this.X, y, this.Style, this.Line(i)).Draw()
+		y -= this.font.cellHeight
+	}
+
+	prompt := this.font.LayoutStyle(this.X, this.inputY(), this.Style, this.Prompt)
+	prompt.Draw()
+	promptWidth := prompt.Width()
+	this.Input.X = this.X + promptWidth
+	this.Input.Y = this.inputY()
+	this.Input.Width = this.Width - promptWidth
+	this.Input.Draw()
+}
@@ -0,0 +1,70 @@
+package gltext
+
+import (
+	"fmt"
+	"unicode"
+)
+
+//ControlPolicy is what laying out text does with control characters other than '\t' and the line breaks, which it lays out itself
+type ControlPolicy int
+
+const (
+	ControlsSkip  ControlPolicy = iota //leave them out
+	ControlsShow                       //draw the Unicode control pictures, such as ␀ and ␛, in their place
+	ControlsError                      //leave them out, and have the layout's Err report the first
+)
+
+//ControlPictures is the charset ControlsShow draws with, which a font must bake or load dynamically to show them
+var ControlPictures = Charset{"Control Pictures", []RuneRange{{0x2400, 0x2421}, {0xFFFD, 0xFFFD}}}
+
+//ControlCharacterError is reported by Layout.Err for a control character laid out under ControlsError; it matches
+//ErrControlCharacter
+type ControlCharacterError struct {
+	Rune   rune
+	Offset int //the byte offset of Rune in the text
+}
+
+func (this *ControlCharacterError) Error() string {
+	return fmt.Sprintf("gltext: control character %U at byte %d", this.Rune, this.Offset)
+}
+
+func (this *ControlCharacterError) Is(target error) bool {
+	return target == ErrControlCharacter
+}
+
+func isLayoutControl(r rune) bool {
+	return r != '\t' && !isLineBreak(r) && unicode.IsControl(r)
+}
+
+//controlPicture returns the rune that shows control character r: its control picture, or the replacement character
+//for the C1 controls, which have none
+func controlPicture(r rune) rune {
+	switch {
+	case r < 0x20:
+		return 0x2400 + r
+	case r == 0x7F:
+		return 0x2421
+	}
+	return 0xFFFD
+}
+
+//control shapes control character ch at byte offset i under the style's policy
+func (this *layoutBuilder) control(ch rune, i int) shapedGlyph {
+	switch this.style.Controls {
+	case ControlsShow:
+		if g, ok := this.font.shapeRune(controlPicture(ch), this.style); ok {
+			return g
+		}
+		return shapedGlyph{index: -1, missing: true}
+	case ControlsError:
+		if this.layout.err == nil {
+			this.layout.err = &ControlCharacterError{ch, i}
+		}
+	}
+	return shapedGlyph{index: -1}
+}
+
+//Err returns the first problem laying out the text found, which is only ever a *ControlCharacterError under ControlsError
+func (this *Layout) Err() error {
+	return this.err
+}
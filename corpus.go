@@ -0,0 +1,37 @@
+package gltext
+
+import (
+	"strings"
+)
+
+//NewFontCorpus bakes exactly the glyphs needed to draw the given strings, such as every localized string of a language,
+//keeping the atlas as small as it can be for embedded targets. drawing anything else leaves the missing runes out.
+func NewFontCorpus(fontPath string, scale int32, dpi float64, width, height float32, corpus ...string) *Font {
+	source := newFontSource(fontPath, scale, dpi, width, height)
+	source.runes = corpusRunes(corpus)
+	return uploadFont(source, generateAtlas(loadFont(fontPath), source, nil))
+}
+
+//corpusRunes returns each rune of the corpus once, in the order they first appear, along with any ligature the corpus
+//would be drawn with
+func corpusRunes(corpus []string) []rune {
+	seen := make(map[rune]bool)
+	runes := make([]rune, 0)
+	add := func(ch rune) {
+		if !seen[ch] {
+			seen[ch] = true
+			runes = append(runes, ch)
+		}
+	}
+	for _, s := range corpus {
+		for _, ch := range s {
+			add(ch)
+		}
+		for _, l := range standardLigatures {
+			if strings.Contains(s, l.text) {
+				add(l.r)
+			}
+		}
+	}
+	return runes
+}
@@ -0,0 +1,61 @@
+package gltext
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Cursor is where the next line of output goes, for drawing successive lines of debug text without tracking y.
+//make one each frame, or Reset it, with the top left corner of the first line.
+type Cursor struct {
+	X, Y       float32
+	Style      Style
+	LabelStyle Style //used by Label for the label, before the value in Style
+
+	font *Font
+	top  float32
+}
+
+func NewCursor(font *Font, x, y float32) *Cursor {
+	return &Cursor{X: x, Y: y, Style: font.style, LabelStyle: font.style, font: font, top: y}
+}
+
+//Printf draws a line, or as many as the result has line breaks, and moves down past it
+func (this *Cursor) Printf(fs string, argv ...interface{}) {
+	this.print(this.X, this.Style, this.font.sprintf(fs, argv...))
+}
+
+//Println draws its operands as fmt.Println would print them, and moves down past them
+func (this *Cursor) Println(argv ...interface{}) {
+	this.print(this.X, this.Style, strings.TrimSuffix(fmt.Sprintln(argv...), "\n"))
+}
+
+//Label draws "label: " in LabelStyle followed by the operands in Style, on one line
+func (this *Cursor) Label(label string, argv ...interface{}) {
+	l := this.font.cachedLayout(this.X, this.Y, 0, this.LabelStyle, label+": ")
+	l.Draw()
+	this.print(this.X+l.Width(), this.Style, strings.TrimSuffix(fmt.Sprintln(argv...), "\n"))
+}
+
+//Skip moves down lines blank lines
+func (this *Cursor) Skip(lines int) {
+	this.Y -= float32(lines) * this.font.cellHeight
+}
+
+//Reset moves back to the first line
+func (this *Cursor) Reset() {
+	this.Y = this.top
+}
+
+func (this *Cursor) print(x float32, style Style, s string) {
+	l := this.font.cachedLayout(x, this.Y, 0, style, s)
+	l.Draw()
+	this.Y -= l.Height()
+}
+
+//Println draws its operands from x, y as fmt.Println would print them, and returns the y of the line after them
+func (this *Font) Println(x, y float32, argv ...interface{}) float32 {
+	c := Cursor{X: x, Y: y, Style: this.style, font: this}
+	c.Println(argv...)
+	return c.Y
+}
@@ -0,0 +1,52 @@
+package gltext
+
+import (
+	"image"
+	"image/draw"
+)
+
+//AddGlyph puts img into the atlas under r, so that r draws as the image wherever it appears in text.
+//Private use runes (U+E000 to U+F8FF) are a good choice for custom bullets and controller button icons.
+//The image's bottom edge sits on the baseline and it advances by its own width; adding a rune again replaces its image.
+//It must be called on the GL thread.
+func (this *Font) AddGlyph(r rune, img image.Image) error {
+	a := this.atlas
+	size := img.Bounds().Size()
+	if size.X > a.cellWidth || size.Y > a.cellPixels {
+		return &GlyphTooLargeError{Rune: r, Width: size.X, Height: size.Y, MaxWidth: a.cellWidth, MaxHeight: a.cellPixels}
+	}
+
+	cell := image.NewRGBA(image.Rect(0, 0, a.cellWidth, a.cellPixels))
+	top := a.baselinePixels - size.Y
+	if top < 0 {
+		top = 0
+	}
+	draw.Draw(cell, image.Rectangle{image.Pt(0, top), image.Pt(0, top).Add(size)}, img, img.Bounds().Min, draw.Src)
+	advance := float32(size.X) * a.sx
+	ink := Rect{0, -float32(top) * a.sy, advance, float32(size.Y) * a.sy}
+
+	//remembered so that Reload can put them back
+	if this.customGlyphs == nil {
+		this.customGlyphs = make(map[rune]image.Image)
+	}
+	this.customGlyphs[r] = img
+	defer this.atlasChanged()
+
+	if i, exists := a.runes[r]; exists {
+		page, origin := a.cell(i)
+		draw.Draw(page, cell.Bounds().Add(origin), cell, image.ZP, draw.Src)
+		a.offsets[i] = advance
+		a.ink[i] = ink
+		a.evictable[i] = false
+		this.uploadCell(i)
+		return nil
+	}
+
+	if a.add(r, cell, advance, ink) {
+		this.uploadAtlas()
+	} else {
+		this.uploadCell(a.runes[r])
+		this.packQuads()
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package gltext
+
+//DrawAtlas draws the atlas pages side by side with their top left corner at x, y, scale screen pixels to each atlas pixel,
+//over a dark backdrop so that empty cells and packing waste stand out. it is meant for diagnosing bleeding and missing glyphs.
+func (this *Font) DrawAtlas(x, y, scale float32) {
+	this.drawAtlas(x, y, scale, nil)
+}
+
+//DrawAtlasGrid is DrawAtlas with the outline of every cell drawn in color on top
+func (this *Font) DrawAtlasGrid(x, y, scale float32, color Vector4) {
+	this.drawAtlas(x, y, scale, &color)
+}
+
+func (this *Font) drawAtlas(x, y, scale float32, grid *Vector4) {
+	a := this.atlas
+	restore := this.applyClip()
+	for p, page := range a.pages {
+		w := float32(page.Bounds().Dx()) * scale * a.sx
+		h := float32(page.Bounds().Dy()) * scale * a.sy
+		r := Rect{x, y, w, h}
+		this.rects.draw(r.X, r.Y, r.W, r.H, Vector4{0, 0, 0, 0.75})
+		this.rects.drawQuad(quad{
+			rect:    r,
+			color:   Vector4{1, 1, 1, 1},
+			texture: this.textures[p],
+			uv:      Vector4{0, 0, 1, 1},
+		})
+		if grid != nil {
+			this.drawAtlasGrid(r, columns(page, a.cellWidth), page.Bounds().Dy()/a.cellPixels, scale, *grid)
+		}
+		//leave a gap of one cell between pages
+		x += w + float32(a.cellWidth)*scale*a.sx
+	}
+	restore()
+}
+
+//drawAtlasGrid outlines the cells of a page drawn over r, with lines one pixel thick
+func (this *Font) drawAtlasGrid(r Rect, columns, rows int, scale float32, color Vector4) {
+	a := this.atlas
+	cw, ch := float32(a.cellWidth)*scale*a.sx, float32(a.cellPixels)*scale*a.sy
+	for c := 0; c <= columns; c++ {
+		this.rects.draw(r.X+float32(c)*cw, r.Y, a.sx, float32(rows)*ch, color)
+	}
+	for row := 0; row <= rows; row++ {
+		this.rects.draw(r.X, r.Y-float32(row)*ch, float32(columns)*cw, a.sy, color)
+	}
+}
@@ -0,0 +1,76 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+)
+
+//Decal is a layout baked into a texture for mapping onto surfaces in a 3D scene, such as signs and floor markings.
+//the texture holds premultiplied alpha; blend it with ONE, ONE_MINUS_SRC_ALPHA.
+type Decal struct {
+	Texture       gl.Texture
+	Width, Height int     //size of the texture in pixels
+	Aspect        float32 //width over height, for sizing a surface without stretching the text
+}
+
+//DecalVertex is a corner of a decal quad
+type DecalVertex struct {
+	Position [3]float32
+	UV       [2]float32
+}
+
+//NewDecal renders layout's PaddedRect into a texture pixelsHigh tall, independent of its size on screen
+func (this *Font) NewDecal(layout *Layout, pixelsHigh int) *Decal {
+	r := layout.PaddedRect()
+	aspect := (r.W / this.atlas.sx) / (r.H / this.atlas.sy)
+	w := int(float32(pixelsHigh)*aspect + 0.5)
+	return &Decal{
+		Texture: this.renderToTexture(layout, r, w, pixelsHigh),
+		Width:   w,
+		Height:  pixelsHigh,
+		Aspect:  aspect,
+	}
+}
+
+//Quad returns a triangle strip covering the whole decal, height tall and centred on center, in the plane of the
+//unit vectors right and up
+func (this *Decal) Quad(center, right, up [3]float32, height float32) []DecalVertex {
+	hw, hh := height*this.Aspect/2, height/2
+	corner := func(x, y float32) [3]float32 {
+		var p [3]float32
+		for i := range p {
+			p[i] = center[i] + right[i]*x + up[i]*y
+		}
+		return p
+	}
+	return []DecalVertex{
+		{corner(-hw, hh), [2]float32{0, 1}},
+		{corner(hw, hh), [2]float32{1, 1}},
+		{corner(-hw, -hh), [2]float32{0, 0}},
+		{corner(hw, -hh), [2]float32{1, 0}},
+	}
+}
+
+//Project gives texture coordinates to arbitrary mesh vertices by projecting the decal along its normal from a
+//rectangle whose bottom left corner is origin and whose edges are right and up. coordinates outside zero to one
+//fall off the decal, so clamp to a transparent border or discard them in the shader.
+func (this *Decal) Project(positions [][3]float32, origin, right, up [3]float32) [][2]float32 {
+	dot := func(a, b [3]float32) float32 {
+		return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+	}
+	rr, uu := dot(right, right), dot(up, up)
+	uv := make([][2]float32, len(positions))
+	for i, p := range positions {
+		d := [3]float32{p[0] - origin[0], p[1] - origin[1], p[2] - origin[2]}
+		if rr > 0 {
+			uv[i][0] = dot(d, right) / rr
+		}
+		if uu > 0 {
+			uv[i][1] = dot(d, up) / uu
+		}
+	}
+	return uv
+}
+
+func (this *Decal) Delete() {
+	this.Texture.Delete()
+}
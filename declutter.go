@@ -0,0 +1,64 @@
+package gltext
+
+import "sort"
+
+//Label is a layout competing with others for space on screen, as on a map
+type Label struct {
+	Layout   *Layout
+	X, Y     float32 //where the label would like to be; Declutter moves the layout relative to this
+	Priority float32 //higher priority labels claim their space first
+	//Nudges are offsets from X, Y to try in turn when the label doesn't fit where it would like to be
+	Nudges  [][2]float32
+	Visible bool //set by Declutter
+}
+
+//NewLabel makes a label that would like to stay where layout already is
+func NewLabel(layout *Layout, priority float32) *Label {
+	return &Label{Layout: layout, X: layout.x, Y: layout.y, Priority: priority}
+}
+
+//Declutter places labels in order of priority, moving each to the first of its position and nudges where its
+//PaddedRect, grown by margin, overlaps no label already placed. labels that fit nowhere are marked hidden.
+//it can be called every frame, since placement always starts again from each label's X, Y.
+func Declutter(labels []*Label, margin float32) {
+	order := make([]*Label, len(labels))
+	copy(order, labels)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Priority > order[j].Priority })
+
+	placed := make([]Rect, 0, len(order))
+	for _, label := range order {
+		label.Visible = false
+		candidates := append([][2]float32{{0, 0}}, label.Nudges...)
+		for _, nudge := range candidates {
+			label.Layout.MoveTo(label.X+nudge[0], label.Y+nudge[1])
+			r := label.Layout.PaddedRect()
+			r = Rect{r.X - margin, r.Y + margin, r.W + 2*margin, r.H + 2*margin}
+			if !overlapsAny(r, placed) {
+				placed = append(placed, r)
+				label.Visible = true
+				break
+			}
+		}
+		if !label.Visible {
+			label.Layout.MoveTo(label.X, label.Y)
+		}
+	}
+}
+
+func overlapsAny(r Rect, others []Rect) bool {
+	for _, o := range others {
+		if i := r.Intersect(o); i.W > 0 && i.H > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+//DrawLabels draws the labels Declutter left visible
+func DrawLabels(labels []*Label) {
+	for _, label := range labels {
+		if label.Visible {
+			label.Layout.Draw()
+		}
+	}
+}
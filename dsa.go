@@ -0,0 +1,86 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+)
+
+//directStateAccess reports whether the driver has OpenGL 4.5's direct state access, with which the font creates and
+//updates its textures and buffers without binding them, leaving the host renderer's bindings alone
+func directStateAccess() bool {
+	return glVersionAtLeast(4, 5)
+}
+
+//newBuffer makes a buffer. with direct state access it must be created outright, since it may never be bound.
+func newBuffer(dsa bool) gl.Buffer {
+	if dsa {
+		return gl.CreateBuffer()
+	}
+	return gl.GenBuffer()
+}
+
+//bufferData replaces the contents of b, binding it to target only without direct state access
+func bufferData(dsa bool, b gl.Buffer, target gl.GLenum, size int, data interface{}, usage gl.GLenum) {
+	if dsa {
+		gl.NamedBufferData(b, size, data, usage)
+		return
+	}
+	b.Bind(target)
+	gl.BufferData(target, size, data, usage)
+	b.Unbind(target)
+}
+
+//newPageStorage replaces the texture of page p, or makes one for a new page, with immutable storage of the page's size
+//in format with levels mip levels. immutable textures can't be resized, so a page that grows gets a new texture.
+func (this *Font) newPageStorage(p, levels int, format gl.GLenum) gl.Texture {
+	img := this.atlas.pages[p]
+	tex := gl.CreateTexture(gl.TEXTURE_2D)
+	gl.TextureParameteri(tex, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TextureParameteri(tex, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TextureParameteri(tex, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TextureParameteri(tex, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TextureStorage2D(tex, levels, format, img.Bounds().Dx(), img.Bounds().Dy())
+	if p < len(this.textures) {
+		this.textures[p].Delete()
+		this.textures[p] = tex
+	} else {
+		this.textures = append(this.textures, tex)
+	}
+	return tex
+}
+
+//uploadPageDirect uploads page p to a new texture without binding it
+func (this *Font) uploadPageDirect(p int) {
+	img := this.atlas.pages[p]
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	if this.compression != CompressNone {
+		tex := this.newPageStorage(p, 1, this.compression.internalFormat())
+		data := compressRegion(img, img.Bounds(), this.compression)
+		gl.CompressedTextureSubImage2D(tex, 0, 0, 0, w, h, this.compression.internalFormat(), len(data), data)
+		return
+	}
+	tex := this.newPageStorage(p, 1, gl.RGBA8)
+	gl.TextureSubImage2D(tex, 0, 0, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE, img.Pix)
+}
+
+//readPageDirect replaces page p's texture with the mip levels read from a KTX2 file
+func (this *Font) readPageDirect(p int, c AtlasCompression, levels [][]byte) {
+	img := this.atlas.pages[p]
+	format := gl.GLenum(gl.RGBA8)
+	if c != CompressNone {
+		format = c.internalFormat()
+	}
+	tex := this.newPageStorage(p, len(levels), format)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	for i, level := range levels {
+		w, h := maxInt(img.Bounds().Dx()>>uint(i), 1), maxInt(img.Bounds().Dy()>>uint(i), 1)
+		if c == CompressNone {
+			gl.TextureSubImage2D(tex, i, 0, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE, level)
+		} else {
+			gl.CompressedTextureSubImage2D(tex, i, 0, 0, w, h, format, len(level), level)
+		}
+	}
+	if len(levels) > 1 {
+		gl.TextureParameteri(tex, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+	}
+}
@@ -0,0 +1,146 @@
+package gltext
+
+import (
+	"code.google.com/p/freetype-go/freetype/truetype"
+	"image"
+	"image/draw"
+)
+
+//SetDynamicGlyphs makes the font rasterize runes missing from its atlas the first time they are laid out, so that
+//arbitrary Unicode input works without baking every range up front. once capacity glyphs have been loaded this way
+//the least recently used of them makes way for each new one, unless the text being laid out uses every one of them;
+//the glyphs the font was built with are never evicted.
+//zero turns it off. it must be called on the GL thread, as must layout while it is on.
+func (this *Font) SetDynamicGlyphs(capacity int) {
+	this.dynamic = capacity
+}
+
+//NewDynamicFont starts with an empty atlas a few cells in size and loads glyphs as text needs them, growing the
+//texture as they arrive, so that ASCII-only programs stay small while CJK-heavy ones still work.
+//capacity is passed to SetDynamicGlyphs.
+func NewDynamicFont(fontPath string, scale int32, dpi float64, width, height float32, capacity int) (*Font, error) {
+	source := newFontSource(fontPath, scale, dpi, width, height)
+	source.runes = []rune{}
+	font, err := parseFontFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
+	f := uploadFont(source, generateAtlas(font, source, nil))
+	f.SetDynamicGlyphs(capacity)
+	return f, nil
+}
+
+//AtlasGeneration changes whenever glyphs are evicted or moved, after which layouts kept across frames must be rebuilt
+func (this *Font) AtlasGeneration() int {
+	return this.generation
+}
+
+//loadGlyph rasterizes ch into the atlas on demand, evicting a dynamic glyph if there is no room
+func (this *Font) loadGlyph(ch rune) (int, bool) {
+	a := this.atlas
+	if this.dynamic <= 0 || a.font == nil || a.font.Index(ch) == 0 {
+		return 0, false
+	}
+	cell := rasterizeGlyphs(a.font, []rune{ch}, a.source.scale, a.source.dpi, a.cellWidth, a.cellPixels, a.baselinePixels, nil)[0]
+	advance, ink := a.metrics(truetype.NewGlyphBuf(), ch)
+
+	if a.dynamicCount() >= this.dynamic {
+		if i := a.leastRecentlyUsed(); i >= 0 {
+			a.replace(i, ch, cell, advance, ink)
+			this.uploadCell(i)
+			this.atlasChanged()
+			return i, true
+		}
+	}
+	grew := a.add(ch, cell, advance, ink)
+	i := a.runes[ch]
+	a.evictable[i] = true
+	if grew {
+		this.uploadAtlas()
+	} else {
+		this.uploadCell(i)
+		this.packQuads()
+	}
+	return i, true
+}
+
+//CompactAtlas drops the dynamic glyphs that haven't been used since the last compaction and packs the rest
+//together into textures no larger than they need
+func (this *Font) CompactAtlas() {
+	old := this.atlas
+	kept := make([]int, 0, len(old.offsets))
+	for i := range old.offsets {
+		if !old.evictable[i] || old.used[i] > old.compacted {
+			kept = append(kept, i)
+		}
+	}
+	this.atlas = old.rebuild(kept, old.maxSize)
+	this.atlas.compacted = old.clock
+	this.uploadAtlas()
+	this.atlasChanged()
+}
+
+//atlasChanged forgets everything that depends on which glyph is where
+func (this *Font) atlasChanged() {
+	this.generation++
+	this.layouts.clear()
+	this.shapes.clear()
+	if this.halo != nil {
+		this.halo.texture.Delete()
+		this.halo = nil
+	}
+}
+
+func (this *atlas) touch(i int) {
+	this.clock++
+	this.used[i] = this.clock
+}
+
+func (this *atlas) dynamicCount() int {
+	n := 0
+	for _, e := range this.evictable {
+		if e {
+			n++
+		}
+	}
+	return n
+}
+
+//leastRecentlyUsed returns the evictable glyph used longest ago, or -1 if there are none that the layout being built
+//doesn't use
+func (this *atlas) leastRecentlyUsed() int {
+	oldest := -1
+	for i, e := range this.evictable {
+		if this.pinned && this.used[i] > this.pinnedAt {
+			continue
+		}
+		if e && (oldest < 0 || this.used[i] < this.used[oldest]) {
+			oldest = i
+		}
+	}
+	return oldest
+}
+
+//replace puts r's glyph into the cell of glyph i in place of whatever was there
+func (this *atlas) replace(i int, r rune, cell image.Image, advance float32, ink Rect) {
+	delete(this.runes, this.glyphs[i])
+	page, origin := this.cell(i)
+	draw.Draw(page, image.Rect(0, 0, this.cellWidth, this.cellPixels).Add(origin), cell, cell.Bounds().Min, draw.Src)
+	this.offsets[i], this.ink[i], this.glyphs[i] = advance, ink, r
+	this.runes[r] = i
+	this.used[i] = this.clock
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
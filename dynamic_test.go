@@ -0,0 +1,83 @@
+package gltext
+
+import (
+	"code.google.com/p/freetype-go/freetype"
+	"github.com/jimarnold/gl"
+	"golang.org/x/image/font/gofont/goregular"
+	"testing"
+)
+
+//newTestDynamicFont builds a font of runes, every one of them loaded on demand, without a GL context. its atlas
+//has room for more cells, so that loading glyphs only uploads cells.
+func newTestDynamicFont(t *testing.T, runes string, capacity int) *Font {
+	ttf, err := freetype.ParseFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := fontSource{scale: 16, dpi: 72, width: 800, height: 600, runes: []rune(runes)}
+	a := generateAtlas(ttf, source, nil)
+	for i := range a.evictable {
+		a.evictable[i] = true
+	}
+	for a.capacity() < len(a.offsets)+4 {
+		a.grow()
+	}
+	a.buildQuads()
+	font := &Font{atlas: a, cellHeight: a.cellHeight, baseline: a.baseline, style: DefaultStyle(), textures: make([]gl.Texture, len(a.pages))}
+	font.packQuads()
+	font.SetDynamicGlyphs(capacity)
+	return font
+}
+
+//checkGlyphs fails unless every glyph drawn by layout is still in the atlas as the rune it was laid out for
+func checkGlyphs(t *testing.T, font *Font, layout *Layout) {
+	t.Helper()
+	for _, g := range layout.glyphs {
+		if g.index >= 0 && font.atlas.glyphs[g.index] != g.r {
+			t.Errorf("%q is drawn with the glyph of %q", g.r, font.atlas.glyphs[g.index])
+		}
+	}
+}
+
+func TestDynamicFontFull(t *testing.T) {
+	font := newTestDynamicFont(t, "abcd", 4)
+	font.shape("ab", font.style)
+	glyphs := font.shape("e", font.style)
+	if i := glyphs[0].index; i < 0 || font.atlas.glyphs[i] != 'e' {
+		t.Fatalf("e was shaped as glyph %d", i)
+	}
+	if n := font.ShapeCacheStats().Entries; n != 0 {
+		t.Errorf("%d words cached across the eviction, want 0", n)
+	}
+	font.shape("e", font.style)
+	if n := font.ShapeCacheStats().Entries; n != 1 {
+		t.Errorf("%d words cached after the eviction, want 1", n)
+	}
+	checkGlyphs(t, font, font.cachedLayout(0, 0, 0, font.style, "f"))
+	if key, _ := newLayoutKey("f", 0, font.style); font.layouts.current[key] != nil {
+		t.Error("a layout built across an eviction was cached")
+	}
+}
+
+func TestDynamicFontKeepsGlyphsInUse(t *testing.T) {
+	//a word from the shape cache is used again, though b was used after it
+	font := newTestDynamicFont(t, "ab", 2)
+	font.shape("a", font.style)
+	font.shape("b", font.style)
+	checkGlyphs(t, font, font.layoutText(0, 0, 0, font.style, "a c", nil))
+
+	//every glyph is in the layout by the time c is loaded
+	font = newTestDynamicFont(t, "ab", 2)
+	checkGlyphs(t, font, font.layoutText(0, 0, 0, font.style, "ba c", nil))
+
+	//a glyph drawn from a cached layout is used, though b was shaped after it
+	font = newTestDynamicFont(t, "ab", 2)
+	font.shape("a", font.style)
+	font.shape("b", font.style)
+	a := font.atlas.runes['a']
+	font.batchGlyph(0, a, 0, 0, 1, 0, nil, 0)
+	font.shape("c", font.style)
+	if font.atlas.glyphs[a] != 'a' {
+		t.Error("a was evicted while it was being drawn")
+	}
+}
@@ -0,0 +1,93 @@
+package gltext
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jimarnold/gl"
+)
+
+var (
+	ErrFontParse              = errors.New("gltext: cannot parse font")
+	ErrGlyphMissing           = errors.New("gltext: glyph missing from atlas")
+	ErrShaderCompile          = errors.New("gltext: shader failed to compile")
+	ErrGlyphTooLarge          = errors.New("gltext: glyph image larger than an atlas cell")
+	ErrCompressionUnsupported = errors.New("gltext: texture compression format not supported by the driver")
+	ErrKTX2Unsupported        = errors.New("gltext: not a KTX2 file gltext can load")
+	ErrAtlasMismatch          = errors.New("gltext: texture does not match the font's atlas")
+	ErrGlyphPathUnsupported   = errors.New("gltext: glyph path not supported by the driver")
+	ErrControlCharacter       = errors.New("gltext: control character in text")
+	ErrColorSyntax            = errors.New("gltext: cannot parse color")
+)
+
+//FontParseError is returned when a font file was read but is not a valid TrueType font; it matches ErrFontParse
+type FontParseError struct {
+	Path string
+	Err  error
+}
+
+func (this *FontParseError) Error() string {
+	return fmt.Sprintf("gltext: cannot parse font %s: %v", this.Path, this.Err)
+}
+
+func (this *FontParseError) Unwrap() error {
+	return this.Err
+}
+
+func (this *FontParseError) Is(target error) bool {
+	return target == ErrFontParse
+}
+
+//GlyphMissingError reports a rune that is not in the font's atlas; it matches ErrGlyphMissing
+type GlyphMissingError struct {
+	Rune rune
+}
+
+func (this *GlyphMissingError) Error() string {
+	return fmt.Sprintf("gltext: glyph missing from atlas: %q (%U)", this.Rune, this.Rune)
+}
+
+func (this *GlyphMissingError) Is(target error) bool {
+	return target == ErrGlyphMissing
+}
+
+//ColorSyntaxError is returned by ParseColor for a string that is neither a named nor a hex color; it matches ErrColorSyntax
+type ColorSyntaxError struct {
+	Text string
+}
+
+func (this *ColorSyntaxError) Error() string {
+	return fmt.Sprintf("gltext: cannot parse color %q", this.Text)
+}
+
+func (this *ColorSyntaxError) Is(target error) bool {
+	return target == ErrColorSyntax
+}
+
+//GlyphTooLargeError is returned by AddGlyph for an image that does not fit in the font's atlas cells; it matches ErrGlyphTooLarge
+type GlyphTooLargeError struct {
+	Rune                rune
+	Width, Height       int
+	MaxWidth, MaxHeight int
+}
+
+func (this *GlyphTooLargeError) Error() string {
+	return fmt.Sprintf("gltext: glyph image for %U is %dx%d, larger than the %dx%d atlas cell", this.Rune, this.Width, this.Height, this.MaxWidth, this.MaxHeight)
+}
+
+func (this *GlyphTooLargeError) Is(target error) bool {
+	return target == ErrGlyphTooLarge
+}
+
+//ShaderCompileError carries the driver's info log for a shader that failed to compile; it matches ErrShaderCompile
+type ShaderCompileError struct {
+	Type    gl.GLenum
+	InfoLog string
+}
+
+func (this *ShaderCompileError) Error() string {
+	return fmt.Sprintf("gltext: shader failed to compile: %s", this.InfoLog)
+}
+
+func (this *ShaderCompileError) Is(target error) bool {
+	return target == ErrShaderCompile
+}
@@ -0,0 +1,176 @@
+package gltext
+
+import (
+	"image"
+	"math"
+)
+
+//LoadImageFont builds a font's atlas without touching OpenGL, for use on servers and in tools where there is no
+//context. width and height are the size of the notional screen that draw units are measured against.
+//such a font can lay text out and render it with Layout.Image, but none of its drawing methods may be called.
+func LoadImageFont(fontPath string, scale int32, dpi float64, width, height float32) (*Font, error) {
+	source := newFontSource(fontPath, scale, dpi, width, height)
+	font, err := parseFontFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
+	a := generateAtlas(font, source, nil)
+	return &Font{
+		atlas:      a,
+		cellHeight: a.cellHeight,
+		baseline:   a.baseline,
+		style:      DefaultStyle(),
+		source:     source,
+	}, nil
+}
+
+//Image rasterizes the layout on the CPU into a transparent image covering its PaddedRect, with the same colors,
+//highlights, shadow, outline and decorations that Draw would give it. backgrounds are drawn as plain rectangles,
+//and halos, animation and inline elements are left out. it doesn't need an OpenGL context.
+func (this *Layout) Image() *image.RGBA {
+	return this.image(this.style)
+}
+
+func (this *Layout) image(style Style) *image.RGBA {
+	font := this.font
+	style = font.themed(style, 1)
+	a := font.atlas
+	bounds := this.PaddedRect()
+	_, _, w, h := font.pixelRect(bounds)
+	canvas := &imageCanvas{image.NewRGBA(image.Rect(0, 0, w, h)), bounds, a}
+
+	if style.Background.Color[3] > 0 {
+		pad := style.Background.Padding
+		r := this.Rect()
+		canvas.fill(Rect{r.X - pad, r.Y + pad, r.W + 2*pad, r.H + 2*pad}, style.Background.Color)
+	}
+	for _, h := range this.highlights {
+		for _, r := range this.SelectionRects(h.start, h.end) {
+			canvas.fill(r, h.color)
+		}
+	}
+	if style.Shadow.Color[3] > 0 {
+		canvas.glyphs(this.glyphs, style.Shadow.Offset[0], style.Shadow.Offset[1], style.Shadow.Color, false)
+	}
+	if style.Outline.Width > 0 {
+		for _, d := range outlineDirections {
+			canvas.glyphs(this.glyphs, d[0]*style.Outline.Width, d[1]*style.Outline.Width, style.Outline.Color, false)
+		}
+	}
+	canvas.glyphs(this.glyphs, 0, 0, style.Color, true)
+	for _, r := range this.decorationRects(style) {
+		canvas.fill(r, style.Color)
+	}
+	rects, colors := this.spanDecorations(style, 1)
+	for i, r := range rects {
+		canvas.fill(r, colors[i])
+	}
+	return canvas.image
+}
+
+//imageCanvas composites onto an image whose top left pixel is at the top left of bounds
+type imageCanvas struct {
+	image  *image.RGBA
+	bounds Rect
+	atlas  *atlas
+}
+
+//pixel converts a point in draw units to pixels from the top left of the image
+func (this *imageCanvas) pixel(x, y float32) (float32, float32) {
+	return (x - this.bounds.X) / this.atlas.sx, (this.bounds.Y - y) / this.atlas.sy
+}
+
+func (this *imageCanvas) fill(r Rect, color Vector4) {
+	x0, y0 := this.pixel(r.X, r.Y)
+	x1, y1 := this.pixel(r.X+r.W, r.Y-r.H)
+	b := image.Rect(int(x0+0.5), int(y0+0.5), int(x1+0.5), int(y1+0.5)).Intersect(this.image.Bounds())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			this.blend(x, y, color, 1)
+		}
+	}
+}
+
+func (this *imageCanvas) glyphs(glyphs []layoutGlyph, dx, dy float32, color Vector4, perGlyph bool) {
+	for _, g := range glyphs {
+		if g.index < 0 {
+			continue
+		}
+		c := color
+		if perGlyph && g.color != nil {
+			c = *g.color
+		}
+		this.glyph(g, dx, dy, c)
+	}
+}
+
+//glyph samples the glyph's atlas cell for each pixel it covers once scaled and turned about its baseline origin
+func (this *imageCanvas) glyph(g layoutGlyph, dx, dy float32, color Vector4) {
+	a := this.atlas
+	ox, oy := this.pixel(g.x+g.inset+dx, g.y+dy)
+	oy += float32(a.baselinePixels)
+	scale := g.drawScale()
+	sin, cos := math.Sincos(float64(g.angle))
+	s, c := float32(sin), float32(cos)
+
+	//cell pixels relative to the baseline origin, with y down, mapped forward to find the area covered
+	toImage := func(u, v float32) (float32, float32) {
+		u, v = u*scale, v*scale
+		return ox + u*c + v*s, oy - u*s + v*c
+	}
+	top := -float32(a.baselinePixels)
+	bottom := float32(a.cellPixels) + top
+	right := float32(a.cellWidth)
+	minX, minY := toImage(0, top)
+	maxX, maxY := minX, minY
+	for _, p := range [][2]float32{{right, top}, {0, bottom}, {right, bottom}} {
+		x, y := toImage(p[0], p[1])
+		minX, maxX = min32(minX, x), max32(maxX, x)
+		minY, maxY = min32(minY, y), max32(maxY, y)
+	}
+	area := image.Rect(int(minX), int(minY), int(maxX)+1, int(maxY)+1).Intersect(this.image.Bounds())
+
+	page, origin := a.cell(g.index)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			//invert the mapping from the centre of the pixel back into the cell
+			px, py := float32(x)+0.5-ox, float32(y)+0.5-oy
+			u, v := (px*c-py*s)/scale, (px*s+py*c)/scale
+			coverage := this.sample(page, origin, u, v-top)
+			if coverage > 0 {
+				this.blend(x, y, color, coverage)
+			}
+		}
+	}
+}
+
+//sample bilinearly filters the alpha of the cell at origin on page, at u, v pixels from its top left corner
+func (this *imageCanvas) sample(page *image.RGBA, origin image.Point, u, v float32) float32 {
+	a := this.atlas
+	u, v = u-0.5, v-0.5
+	x0, y0 := int(math.Floor(float64(u))), int(math.Floor(float64(v)))
+	fx, fy := u-float32(x0), v-float32(y0)
+	alpha := func(x, y int) float32 {
+		if x < 0 || y < 0 || x >= a.cellWidth || y >= a.cellPixels {
+			return 0
+		}
+		return float32(page.Pix[page.PixOffset(origin.X+x, origin.Y+y)+3]) / 255
+	}
+	top := alpha(x0, y0)*(1-fx) + alpha(x0+1, y0)*fx
+	bottom := alpha(x0, y0+1)*(1-fx) + alpha(x0+1, y0+1)*fx
+	return top*(1-fy) + bottom*fy
+}
+
+//blend composites color over the pixel at x, y with the given coverage, keeping the image premultiplied
+func (this *imageCanvas) blend(x, y int, color Vector4, coverage float32) {
+	alpha := color[3] * coverage
+	if alpha <= 0 {
+		return
+	}
+	i := this.image.PixOffset(x, y)
+	pix := this.image.Pix[i : i+4]
+	for k := 0; k < 3; k++ {
+		pix[k] = uint8(color[k]*alpha*255 + float32(pix[k])*(1-alpha) + 0.5)
+	}
+	pix[3] = uint8(alpha*255 + float32(pix[3])*(1-alpha) + 0.5)
+}
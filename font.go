@@ -1,230 +1,462 @@
 package gltext
 
 import (
-	"errors"
-	"fmt"
 	"code.google.com/p/freetype-go/freetype"
 	"code.google.com/p/freetype-go/freetype/truetype"
-	"github.com/go-gl/glh"
 	"github.com/jimarnold/gl"
 	"image"
 	"io/ioutil"
-	"log"
-	"reflect"
+	"os"
+	"time"
 )
 
 type Font struct {
 	program        gl.Program
 	vs, fs         gl.Shader
-	positionAttrib gl.AttribLocation
-	colorUniform   gl.UniformLocation
-	offsetUniform  gl.UniformLocation
-	vao            gl.VertexArray
+	vao            *vertexArray
 	vbo            gl.Buffer
-	offsets        []float32
-	color          []float32
+	stream         *vertexStream //replaces vbo from OpenGL 4.4
+	baseVertex     int //where in stream the uploaded batch starts
+	ebo            gl.Buffer
+	indexQuads     int //how many quads ebo has indices for
+	quads          []glyphVertex //the atlas quads, packed for the GPU
+	batch          []batchVertex
+	runs           []glyphRun
+	runStart       int //the first run batchGlyph may extend
+	path           GlyphPath
+	storage        *glyphStorage
+	points         *glyphPoints
+	ubo            gl.Buffer //the DrawParams of every pass queued since the last flush
+	paramStride    int //floats from one DrawParams to the next
+	params         []float32
+	draws          []glyphDraw
+	bound          int //the page bound while flushing, -1 for the halo field
+	calls          drawCalls
+	stats          DrawStats
+	shapesAtReset  ShapeCacheStats
+	inventory      TextInventory
+	inventoryOn    bool
+	textures       []gl.Texture //one per atlas page
+	dsa            bool //create and update textures and buffers through OpenGL 4.5 direct state access
+	atlas          *atlas
+	compression    AtlasCompression
+	cellHeight     float32
+	baseline       float32
+	style          Style
+	palette        Palette
+	indexedColors  []Vector4
+	rects          *rectRenderer
+	clip           *Rect
+	customGlyphs   map[rune]image.Image
+	missingGlyph   func(MissingGlyph) rune
+	locale         *Locale //set by SetLocale
+	numberBuf      []byte
+	halo           *haloField
+	layouts        layoutCache
+	shapes         shapeCache
+	dynamic        int //how many glyphs may be loaded on demand
+	generation     int
+	source         fontSource
+}
+
+//fontSource records how a Font was built so that it can be rebuilt by Reload
+type fontSource struct {
+	path          string
+	scale         int32
+	dpi           float64
+	width, height float32
+	modTime       time.Time
+	runes         []rune //the runes to bake, or nil for printable ASCII
+	inkAdvance    bool   //advance by at least the ink width, for icon fonts
 }
 
 type Vector4 [4]float32
 
 func NewFont(fontPath string, scale int32, dpi float64, width, height float32) *Font {
+	return NewFontWithProgress(fontPath, scale, dpi, width, height, nil)
+}
+
+//LoadFont is like NewFont but returns an error instead of exiting when the font file cannot be read or parsed
+func LoadFont(fontPath string, scale int32, dpi float64, width, height float32) (*Font, error) {
+	source := newFontSource(fontPath, scale, dpi, width, height)
+	font, err := parseFontFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
+	return uploadFont(source, generateAtlas(font, source, nil)), nil
+}
+
+//progress is called with the number of glyphs rasterized so far, for driving loading screens
+func NewFontWithProgress(fontPath string, scale int32, dpi float64, width, height float32, progress func(done, total int)) *Font {
+	source := newFontSource(fontPath, scale, dpi, width, height)
 	font := loadFont(fontPath)
-	coords, texture, offsets := generateAtlas(font, scale, dpi, width, height)
-	program := createProgram()
+	return uploadFont(source, generateAtlas(font, source, progress))
+}
+
+func newFontSource(fontPath string, scale int32, dpi float64, width, height float32) fontSource {
+	source := fontSource{path: fontPath, scale: scale, dpi: dpi, width: width, height: height}
+	source.stat()
+	return source
+}
 
-	vao := gl.GenVertexArray()
-	vao.Bind()
+func (this *fontSource) stat() {
+	if info, err := os.Stat(this.path); err == nil {
+		this.modTime = info.ModTime()
+	}
+}
+
+func uploadFont(source fontSource, a *atlas) *Font {
+	//a driver that can't hold pages as large as MaxAtlasPageSize gets more, smaller pages instead
+	if limit := maxTextureSize(); limit > 0 && limit < a.maxSize {
+		all := make([]int, len(a.offsets))
+		for i := range all {
+			all[i] = i
+		}
+		a = a.rebuild(all, limit)
+	}
+	program := createProgram(GlyphVertices)
+	dsa := directStateAccess()
 
+	//glyphs are streamed into vbo as they are drawn, and drawn through the quad indices in ebo
 	vbo := gl.GenBuffer()
-	vbo.Bind(gl.ARRAY_BUFFER)
-	gl.BufferData(gl.ARRAY_BUFFER, int(reflect.TypeOf(coords[0]).Size())*len(coords), coords, gl.STATIC_DRAW)
-
-	positionAttrib := program.GetAttribLocation("position")
-	positionAttrib.AttribPointer(4, gl.FLOAT, false, 0, nil)
-	positionAttrib.EnableArray()
-	vbo.Unbind(gl.ARRAY_BUFFER)
-
-	textureUniform := program.GetUniformLocation("tex")
-	offsetUniform := program.GetUniformLocation("offset")
-	colorUniform := program.GetUniformLocation("color")
-
-	gl.ActiveTexture(gl.TEXTURE0)
-	tex := gl.GenTexture()
-	tex.Bind(gl.TEXTURE_2D)
-	textureUniform.Uniform1i(0)
-
-	/* We require 1 byte alignment when uploading texture data */
-	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
-	/* Clamping to edges is important to prevent artifacts when scaling */
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
-	/* Linear filtering usually looks best for text */
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, texture.Bounds().Dx(), texture.Bounds().Dy(), 0, gl.RGBA, gl.UNSIGNED_BYTE, texture.Pix)
-
-	vao.Unbind()
-
-	return &Font {
+	ebo := newBuffer(dsa)
+
+	ubo := newBuffer(dsa)
+
+	font := &Font {
 		program:program,
-		vao:vao,
 		vbo:vbo,
-		positionAttrib:positionAttrib,
-		offsetUniform:offsetUniform,
-		colorUniform:colorUniform,
-		offsets:offsets,
-		color:[]float32{1,1,1,1}}
+		ebo:ebo,
+		ubo:ubo,
+		paramStride:drawParamsStride(),
+		dsa:dsa,
+		atlas:a,
+		cellHeight:a.cellHeight,
+		baseline:a.baseline,
+		style:DefaultStyle(),
+		rects:newRectRenderer(),
+		source:source}
+	font.vao = newVertexArray(font.setupVertices)
+	font.rects.calls = &font.calls
+	font.initProgram()
+	font.uploadAtlas()
+	font.growIndices(0)
+	if glVersionAtLeast(4, 4) {
+		font.setStream(streamGlyphs)
+	}
+	return font
+}
+
+//initProgram sets the uniforms of the glyph program that stay the same from draw to draw
+func (this *Font) initProgram() {
+	program, a := this.program, this.atlas
+	if this.path != GlyphStorage {
+		program.UniformBlockBinding(program.GetUniformBlockIndex("DrawParams"), drawParamsBinding)
+	}
+	program.Use()
+	program.GetUniformLocation("tex").Uniform1i(0)
+	program.GetUniformLocation("baseline").Uniform1f(1 - a.baseline)
+	program.GetUniformLocation("aspect").Uniform1f(a.sy / a.sx)
+	program.Unuse()
+	this.uploadIndexedColors()
+	this.setCompression(this.compression)
 }
 
 func loadFont(fontPath string) *truetype.Font {
-	b, err := ioutil.ReadFile(fontPath)
+	font, err := parseFontFile(fontPath)
 	if err != nil {
-		log.Fatal(err)
+		logFatal(err)
 		return nil
 	}
+
+	return font
+}
+
+func parseFontFile(fontPath string) (*truetype.Font, error) {
+	b, err := ioutil.ReadFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
 	font, err := freetype.ParseFont(b)
 	if err != nil {
-		log.Fatal(err)
-		return nil
+		return nil, &FontParseError{Path: fontPath, Err: err}
 	}
+	return font, nil
+}
 
-	return font
+//Reload rebuilds the atlas from the font file on disk. If the file cannot be parsed the current atlas is kept.
+func (this *Font) Reload() error {
+	source := this.source
+	source.stat()
+	font, err := parseFontFile(source.path)
+	if err != nil {
+		return err
+	}
+	reloaded := uploadFont(source, generateAtlas(font, source, nil))
+	reloaded.keepSettings(this)
+	reloaded.generation = this.generation + 1
+
+	this.Delete()
+	localeMu.Lock()
+	*this = *reloaded
+	localeMu.Unlock()
+	//uploadFont bound the vertex setup and the rect renderer's call counts to reloaded, which is discarded now
+	this.vao.setup = this.setupVertices
+	this.rects.calls = &this.calls
+	return nil
 }
 
-func generateAtlas(font *truetype.Font, scale int32, dpi float64, width, height float32) ([]Vector4, *image.RGBA, []float32) {
-	var low rune = 32
-	var high rune = 127
-	glyphCount := int32(high-low+1)
-	offsets := make([]float32, glyphCount)
+//keepSettings carries over everything set on the font being reloaded that isn't rebuilt from its file. a new setting
+//belongs here too, or Reload quietly resets it.
+func (this *Font) keepSettings(from *Font) {
+	this.style = from.style
+	this.clip = from.clip
+	this.dynamic = from.dynamic
+	this.missingGlyph = from.missingGlyph
+	this.locale = from.locale
+	this.inventoryOn, this.inventory = from.inventoryOn, from.inventory
+	this.SetPalette(from.palette)
+	this.SetAtlasCompression(from.compression)
+	this.SetGlyphPath(from.path)
+	//uploaded to the program SetGlyphPath may have built
+	this.SetIndexedColors(from.indexedColors)
+	for r, img := range from.customGlyphs {
+		this.AddGlyph(r, img)
+	}
+}
+
+//ReloadIfChanged reloads the font if its file has been modified since it was last loaded; call it periodically from the GL thread to watch the file
+func (this *Font) ReloadIfChanged() (bool, error) {
+	info, err := os.Stat(this.source.path)
+	if err != nil {
+		return false, err
+	}
+	if !info.ModTime().After(this.source.modTime) {
+		return false, nil
+	}
+	if err := this.Reload(); err != nil {
+		//don't retry a broken file every frame; wait until it is written again
+		this.source.modTime = info.ModTime()
+		return false, err
+	}
+	return true, nil
+}
+
+func generateAtlas(font *truetype.Font, source fontSource, progress func(done, total int)) *atlas {
+	scale, dpi := source.scale, source.dpi
+	runes := make([]rune, 0)
+	if source.runes == nil {
+		for ch := firstGlyph; ch <= lastGlyph; ch++ {
+			runes = append(runes, ch)
+		}
+		for _, l := range standardLigatures {
+			if font.Index(l.r) != 0 {
+				runes = append(runes, l.r)
+			}
+		}
+	} else {
+		//only bake what the font actually has, so that sweeping a wide range doesn't fill the atlas with empty boxes
+		for _, ch := range source.runes {
+			if font.Index(ch) != 0 {
+				runes = append(runes, ch)
+			}
+		}
+	}
+	glyphCount := int32(len(runes))
 
 	bounds := font.Bounds(scale)
 	gw := float32(bounds.XMax - bounds.XMin)
 	gh := float32(bounds.YMax - bounds.YMin)
-	imageWidth := glh.Pow2(uint32(gw * float32(glyphCount)))
-	imageHeight := glh.Pow2(uint32(gh))
-	imageBounds := image.Rect(0, 0, int(imageWidth), int(imageHeight))
-	sx := float32(2) / width
-	sy := float32(2) / height
-	w := gw * sx
-	h := gh * sy
-	img := image.NewRGBA(imageBounds)
-	c := freetype.NewContext()
-	c.SetDst(img)
-	c.SetClip(img.Bounds())
-	c.SetSrc(image.White)
-	c.SetDPI(dpi)
-	c.SetFontSize(float64(scale))
-	c.SetFont(font)
-
-	var gi int32
-	var gx, gy float32
-	verts := make([]Vector4, 0)
-	texWidth := float32(img.Bounds().Dx())
-	texHeight := float32(img.Bounds().Dy())
-
-	for ch := low; ch <= high; ch++ {
-		index := font.Index(ch)
-		metric := font.HMetric(scale, index)
-
+	imageWidth, imageHeight := atlasSize(int(glyphCount), int(gw), int(gh), MaxAtlasPageSize)
+	imageBounds := image.Rect(0, 0, imageWidth, imageHeight)
+	sx := float32(2) / source.width
+	sy := float32(2) / source.height
+	baseline := int(float64(scale) * dpi / 72)
+	a := newAtlas(image.NewRGBA(imageBounds), int(gw), int(gh), baseline, sx, sy)
+	a.font, a.source = font, source
+
+	cells := rasterizeGlyphs(font, runes, scale, dpi, int(gw), int(gh), baseline, progress)
+
+	glyph := truetype.NewGlyphBuf()
+	for i, ch := range runes {
+		advance, ink := a.metrics(glyph, ch)
 		//the offset is used when drawing a string of glyphs - we will advance a glyph's quad by the width of all previous glyphs in the string
-		offsets[gi] = float32(metric.AdvanceWidth) * sx
-
-		//draw the glyph into the atlas at the correct location
-		pt := freetype.Pt(int(gx), int(gy)+int(c.PointToFix32(float64(scale))>>8))
-		c.DrawString(string(ch), pt)
-
-		tx1 := gx / texWidth
-		ty1 := gy / texHeight
-		tx2 := (gx + gw) / texWidth
-		ty2 := (gy + gh) / texHeight
-		
-		//the x,y coordinates are the same for each quad; only the texture coordinates (stored in z,w) change.
-		//an optimization would be to only store texture coords, but I haven't figured that out yet
-		verts = append(verts, Vector4{-1, 1, tx1, ty1},
-		Vector4{-1 + (w), 1, tx2, ty1},
-		Vector4{-1, 1 - (h), tx1, ty2},
-		Vector4{-1 + (w), 1 - (h), tx2, ty2})
+		a.add(ch, cells[i], advance, ink)
+	}
+	return a
+}
 
-		gx += gw
-		gi++
+//metrics returns the advance and ink bounds of ch in draw units
+func (this *atlas) metrics(glyph *truetype.GlyphBuf, ch rune) (float32, Rect) {
+	font, scale := this.font, this.source.scale
+	sx, sy, baseline := this.sx, this.sy, this.baselinePixels
+	index := font.Index(ch)
+	metric := font.HMetric(scale, index)
+	advance := metric.AdvanceWidth
+
+	ink := Rect{}
+	if err := glyph.Load(font, scale, index, truetype.NoHinting); err == nil {
+		b := glyph.B
+		ink = Rect{float32(b.XMin) * sx, -float32(int32(baseline)-b.YMax) * sy, float32(b.XMax-b.XMin) * sx, float32(b.YMax-b.YMin) * sy}
+		if this.source.inkAdvance && b.XMax > advance {
+			advance = b.XMax
+		}
 	}
-	return verts, img, offsets
+	return float32(advance) * sx, ink
 }
 
 func (this *Font) Printf(x, y float32, fs string, argv ...interface{}) {
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-
-	this.program.Use()
-	this.vao.Bind()
-
-	this.colorUniform.Uniform4fv(1, this.color)
-	totalOffset := float32(0)
+	this.print(x, y, this.sprintf(fs, argv...), this.style)
+}
 
-	s := fmt.Sprintf(fs, argv...)
+//PrintfStyle draws with the given style instead of the font's default
+func (this *Font) PrintfStyle(x, y float32, style Style, fs string, argv ...interface{}) {
+	this.print(x, y, this.sprintf(fs, argv...), style)
+}
 
-	for _, ch := range s {
-		index := int(ch-32)
-		offset := this.offsets[index]
-		this.offsetUniform.Uniform2f(x + totalOffset, y)
-		gl.DrawArrays(gl.TRIANGLE_STRIP, index * 4, 4)
-		totalOffset += offset
-	}
-	this.vao.Unbind()
-	this.program.Unuse()
-	gl.Disable(gl.BLEND)
+func (this *Font) print(x, y float32, s string, style Style) {
+	this.cachedLayout(x, y, 0, style, s).Draw()
 }
 
 func (this *Font) Delete() {
 	this.vs.Delete()
 	this.fs.Delete()
 	this.program.Delete()
+	for _, tex := range this.textures {
+		tex.Delete()
+	}
 	this.vbo.Delete()
+	if this.stream != nil {
+		this.stream.Delete()
+	}
+	this.ebo.Delete()
+	this.ubo.Delete()
+	if this.storage != nil {
+		this.storage.Delete()
+	}
+	if this.points != nil {
+		this.points.Delete()
+	}
 	this.vao.Delete()
+	this.rects.Delete()
+	if this.halo != nil {
+		this.halo.texture.Delete()
+	}
 }
 
-func createProgram() gl.Program {
-	vs,err := NewShader(gl.VERTEX_SHADER,`#version 150
-    in vec4 position;
+func createProgram(path GlyphPath) gl.Program {
+	version, inputs, main := path.shaderInputs()
+	//placeCorner runs in the geometry shader on the point path, and in the vertex shader otherwise
+	stage, name := gl.GLenum(gl.VERTEX_SHADER), "vertex"
+	if path == GlyphPoints {
+		stage, name = gl.GEOMETRY_SHADER, "geometry"
+	}
+	place,err := NewShader(stage, version + inputs + `
     out vec2 texpos;
-    uniform vec2 offset;
-    void main() {
-        gl_Position = vec4(position.xy + offset, 0, 1);
-		texpos = position.zw;
-    }`)
+    out float hue;
+    out vec4 tint;` + path.drawParams(false) + `
+    uniform float baseline; //the y of the baseline in the atlas quads, about which glyphs scale and turn
+    uniform float aspect; //screen width over height, so that turned glyphs aren't sheared
+    uniform vec4 haloField; //for the halo pass, the padded cell width and height over the field's, the padding in pixels, and the field's columns of cells
+    uniform vec2 pixel; //draw units per pixel
+    uniform vec4 indexedColors[16];
+    float noise(vec2 p) {
+        return fract(sin(dot(p, vec2(12.9898, 78.233))) * 43758.5453) - 0.5;
+    }
+    void placeCorner(int corner) {
+        vec4 pos = vec4(position, texcoord);
+        if ((effects & 8) != 0) {
+            //grow the quad to take in the halo, and map it onto the glyph's padded cell in the distance field
+            vec2 side = vec2(corner % 2, corner / 2);
+            pos.xy += (side * 2.0 - 1.0) * vec2(1, -1) * haloField.z * pixel;
+            float column = mod(glyphIndex.y, haloField.w);
+            pos.zw = (vec2(column, (glyphIndex.y - column) / haloField.w) + side) * haloField.xy;
+        }
+        vec2 anchor = vec2(-1, baseline);
+        vec2 d = (pos.xy - anchor) * placement.z * vec2(aspect, 1);
+        vec2 rotation = vec2(cos(placement.w), sin(placement.w));
+        d = vec2(d.x * rotation.x - d.y * rotation.y, d.x * rotation.y + d.y * rotation.x);
+        vec2 p = anchor + d / vec2(aspect, 1) + placement.xy + offset;
+        float phase = animation.x * animation.z + glyphIndex.x * animation.w;
+        if ((effects & 1) != 0) {
+            p.y += sin(phase * 6.2831853) * animation.y;
+        }
+        if ((effects & 2) != 0) {
+            float step = floor(animation.x * animation.z);
+            p += vec2(noise(vec2(glyphIndex.x, step)), noise(vec2(step, glyphIndex.x + 0.5))) * 2.0 * animation.y;
+        }
+        hue = fract(phase);
+        tint = (effects & 16) != 0 ? indexedColors[int(glyphIndex.z)] : vec4(1);
+        gl_Position = vec4(p, 0, 1);
+		texpos = pos.zw;
+    }` + main)
 
 	if err != nil {
-		log.Printf("gltext: Error in vertex shader\n")
-		log.Println(err)
+		logf("gltext: Error in %s shader\n%v", name, err)
+	}
+	shaders := []gl.Shader{place}
+	if path == GlyphPoints {
+		vs,err := NewShader(gl.VERTEX_SHADER, version + pointVertexShader)
+		if err != nil {
+			logf("gltext: Error in vertex shader\n%v", err)
+		}
+		shaders = append(shaders, vs)
 	}
 
-	fs,err := NewShader(gl.FRAGMENT_SHADER,
-	`#version 150
+	fs,err := NewShader(gl.FRAGMENT_SHADER, version + `
     in vec2 texpos;
-    uniform sampler2D tex;
-    uniform vec4 color;
+    in float hue;
+    in vec4 tint;
+    uniform sampler2D tex;` + path.drawParams(true) + `
+    uniform vec2 halo; //inner and outer edge of the halo, as distances from the ink over the field's spread
+    uniform int singleChannel; //the atlas is compressed to coverage alone, in red
     out vec4  fragColor;
+    vec3 rainbow(float h) {
+        return clamp(abs(mod(h * 6.0 + vec3(0, 4, 2), 6.0) - 3.0) - 1.0, 0.0, 1.0);
+    }
     void main(void) {
-        fragColor = texture(tex, texpos) * color;
+        loadParams();
+        vec4 c = color * tint;
+        if ((effects & 8) != 0) {
+            float d = 1.0 - texture(tex, texpos).r;
+            fragColor = vec4(c.rgb, c.a * (1.0 - smoothstep(halo.x, halo.y, d)));
+            return;
+        }
+        if ((effects & 4) != 0) {
+            c.rgb *= rainbow(hue);
+        }
+        vec4 t = texture(tex, texpos);
+        if (singleChannel != 0) {
+            t = vec4(t.r);
+        }
+        fragColor = t * c;
     }`)
 
 	if err != nil {
-		log.Printf("gltext: Error in fragment shader\n")
-		log.Println(err)
+		logf("gltext: Error in fragment shader\n%v", err)
 	}
 
-	return NewProgram(vs, fs)
+	return linkProgram(glyphAttribs, append(shaders, fs)...)
 }
 
 func NewProgram(vs, fs gl.Shader) gl.Program {
+	return linkProgram(nil, vs, fs)
+}
+
+//linkProgram links shaders into a program, giving the named attributes the locations of their indices
+func linkProgram(attribs []string, shaders ...gl.Shader) gl.Program {
 	program := gl.CreateProgram()
 
-	program.AttachShader(vs)
-	program.AttachShader(fs)
+	for _, s := range shaders {
+		program.AttachShader(s)
+	}
+	for i, name := range attribs {
+		program.BindAttribLocation(gl.AttribLocation(i), name)
+	}
 	program.Link()
 	link_ok := program.Get(gl.LINK_STATUS)
 	if link_ok == 0 {
-		log.Printf("gltext: Error linking shader program")
+		logf("gltext: Error linking shader program")
 	}
 
 	return program
@@ -236,8 +468,34 @@ func NewShader(shaderType gl.GLenum, source string) (gl.Shader,error) {
 	s.Compile()
 	compile_ok := s.Get(gl.COMPILE_STATUS)
 	if compile_ok == 0 {
-		return gl.Shader(0),errors.New(s.GetInfoLog())
+		return gl.Shader(0), &ShaderCompileError{Type: shaderType, InfoLog: s.GetInfoLog()}
 	}
 	return s, nil
 }
 
+
+const (
+	firstGlyph rune = 32
+	lastGlyph  rune = 127
+)
+
+func (this *Font) glyphIndex(ch rune) (int, error) {
+	index, ok := this.atlas.runes[ch]
+	if !ok {
+		if index, ok = this.loadGlyph(ch); !ok {
+			return 0, &GlyphMissingError{Rune: ch}
+		}
+	}
+	this.atlas.touch(index)
+	return index, nil
+}
+
+//CheckGlyphs returns a *GlyphMissingError for the first rune in s that this font cannot draw; such runes are skipped when drawing
+func (this *Font) CheckGlyphs(s string) error {
+	for _, ch := range s {
+		if _, err := this.glyphIndex(ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
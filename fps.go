@@ -0,0 +1,49 @@
+package gltext
+
+import (
+	"time"
+)
+
+//FPSCounter averages frame rate over Interval and prints it with a Font. Call Frame once per rendered frame.
+type FPSCounter struct {
+	Interval time.Duration
+
+	font      *Font
+	frames    int
+	start     time.Time
+	fps       float64
+	frameTime time.Duration
+}
+
+func NewFPSCounter(font *Font) *FPSCounter {
+	return &FPSCounter{Interval: time.Second / 2, font: font}
+}
+
+func (this *FPSCounter) Frame() {
+	now := time.Now()
+	if this.start.IsZero() {
+		this.start = now
+		return
+	}
+	this.frames++
+	elapsed := now.Sub(this.start)
+	if elapsed >= this.Interval {
+		this.fps = float64(this.frames) / elapsed.Seconds()
+		this.frameTime = elapsed / time.Duration(this.frames)
+		this.frames = 0
+		this.start = now
+	}
+}
+
+func (this *FPSCounter) FPS() float64 {
+	return this.fps
+}
+
+//FrameTime is the mean time per frame over the last interval
+func (this *FPSCounter) FrameTime() time.Duration {
+	return this.frameTime
+}
+
+func (this *FPSCounter) Draw(x, y float32) {
+	this.font.Printf(x, y, "%.0f fps (%.2f ms)", this.fps, float64(this.frameTime)/float64(time.Millisecond))
+}
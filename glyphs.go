@@ -0,0 +1,38 @@
+package gltext
+
+//Glyph is one rune of a layout as positioned by it, for building custom effects, picking or batching
+type Glyph struct {
+	Rune    rune
+	Index   int     //atlas index of the glyph drawn, or -1 if nothing is drawn for the rune
+	X, Y    float32 //where the glyph's quad is drawn from: its top left corner in draw units
+	Advance float32
+	Cluster int //byte offset of the rune in the laid out string
+	Line    int
+	Scale   float32 //size relative to the font
+	Angle   float32 //radians turned counterclockwise about the baseline origin, for text on paths
+}
+
+//EachGlyph calls f with every glyph of the layout in string order until it returns false
+func (this *Layout) EachGlyph(f func(g Glyph) bool) {
+	for _, g := range this.glyphs {
+		glyph := Glyph{
+			Rune:    g.r,
+			Index:   g.index,
+			X:       g.x + g.inset,
+			Y:       g.y,
+			Advance: g.advance,
+			Cluster: g.byteOffset,
+			Line:    g.line,
+			Scale:   g.drawScale(),
+			Angle:   g.angle,
+		}
+		if !f(glyph) {
+			return
+		}
+	}
+}
+
+//GlyphCount is the number of runes in the layout
+func (this *Layout) GlyphCount() int {
+	return len(this.glyphs)
+}
@@ -0,0 +1,46 @@
+package gltext
+
+import (
+	"unicode"
+)
+
+//graphemeStarts returns the index of the first rune of each grapheme cluster of runes, approximating the Unicode rules
+//closely enough for editing: marks, variation selectors, emoji modifiers, zero width joiner sequences, regional
+//indicator pairs and "\r\n" stay with the rune before them
+func graphemeStarts(runes []rune) []int {
+	starts := make([]int, 0, len(runes))
+	regional := 0 //regional indicators in a row so far
+	for i, r := range runes {
+		if isRegionalIndicator(r) {
+			regional++
+		} else {
+			regional = 0
+		}
+		if i == 0 || !extendsCluster(runes[i-1], r, regional) {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+func extendsCluster(prev, r rune, regional int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == 0x200D || r == 0x200D:
+		return true
+	case unicode.Is(unicode.M, r):
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0100 && r <= 0xE01EF:
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return true
+	case regional > 0 && regional%2 == 0:
+		return true
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -0,0 +1,138 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"image"
+	"math"
+)
+
+//Halo is a soft surround in a single color, as used to keep map labels legible over busy backgrounds.
+//unlike Outline it is drawn in one pass from a distance field, and may be much wider. it is disabled while Width is zero.
+type Halo struct {
+	Width    float32 //pixels from the ink at which the halo starts to fade, up to HaloSpread
+	Softness float32 //pixels over which it fades out
+	Color    Vector4
+}
+
+//HaloSpread is the furthest a halo can reach from the ink, in pixels
+const HaloSpread = 12
+
+//haloPass tells the glyph shaders to draw from the distance field rather than the atlas
+const haloPass Effect = 8
+
+//haloField is a distance field of the atlas, built the first time a halo is drawn.
+//each glyph's cell is padded by HaloSpread on every side so that the halo isn't cut off at the cell's edge, and the
+//padded cells are laid out in rows in one texture no larger than the GL allows.
+type haloField struct {
+	texture gl.Texture
+	glyphs  int //number of atlas glyphs it was built from
+	uniform gl.UniformLocation
+}
+
+func (this *Layout) drawHalo(halo Halo, motion Effect, alpha float32) {
+	font := this.font
+	field := font.haloField()
+	font.program.Use()
+	inner := min32(halo.Width, HaloSpread) / HaloSpread
+	outer := min32(halo.Width+halo.Softness, HaloSpread) / HaloSpread
+	field.uniform.Uniform2f(inner, max32(outer, inner+1e-3))
+	this.drawGlyphs(0, 0, halo.Color, motion|haloPass, false, alpha)
+}
+
+//haloField returns the font's distance field, rebuilding it if glyphs have been added since it was made
+func (this *Font) haloField() *haloField {
+	a := this.atlas
+	if this.halo != nil && this.halo.glyphs == len(a.offsets) {
+		return this.halo
+	}
+	if this.halo != nil {
+		this.halo.texture.Delete()
+	}
+	pad := HaloSpread
+	cw, ch := a.cellWidth+2*pad, a.cellPixels+2*pad
+	limit := a.maxSize
+	if max := maxTextureSize(); max > 0 {
+		limit = max
+	}
+	width, height := atlasSize(len(a.offsets), cw, ch, limit)
+	field := image.NewAlpha(image.Rect(0, 0, width, height))
+	columns := maxInt(width/cw, 1)
+	//glyphs beyond what the largest texture holds go without
+	cells := minInt(len(a.offsets), columns*maxInt(height/ch, 1))
+	for i := 0; i < cells; i++ {
+		distanceField(a, i, field, cellOrigin(i, columns, cw, ch), pad)
+	}
+
+	tex := gl.GenTexture()
+	tex.Bind(gl.TEXTURE_2D)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, field.Bounds().Dx(), field.Bounds().Dy(), 0, gl.RED, gl.UNSIGNED_BYTE, field.Pix)
+	tex.Unbind(gl.TEXTURE_2D)
+
+	this.program.Use()
+	this.program.GetUniformLocation("haloField").Uniform4f(float32(cw)/float32(width), float32(ch)/float32(height), float32(pad), float32(columns))
+	this.program.GetUniformLocation("pixel").Uniform2f(a.sx, a.sy)
+	this.program.Unuse()
+
+	this.halo = &haloField{texture: tex, glyphs: len(a.offsets), uniform: this.program.GetUniformLocation("halo")}
+	return this.halo
+}
+
+//distanceField writes the distance from glyph i's ink, falling from 255 at the ink to 0 at spread pixels away,
+//into the padded cell of field at origin. it uses a two pass chamfer transform, which is close enough for a soft halo.
+func distanceField(a *atlas, i int, field *image.Alpha, origin image.Point, spread int) {
+	w, h := a.cellWidth+2*spread, a.cellPixels+2*spread
+	far := float32(math.MaxFloat32 / 2)
+	d := make([]float32, w*h)
+	page, src := a.cell(i)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			d[y*w+x] = far
+			cx, cy := x-spread, y-spread
+			if cx >= 0 && cy >= 0 && cx < a.cellWidth && cy < a.cellPixels {
+				if page.Pix[page.PixOffset(src.X+cx, src.Y+cy)+3] >= 128 {
+					d[y*w+x] = 0
+				}
+			}
+		}
+	}
+	const straight, diagonal = 1, 1.4142135
+	relax := func(x, y, dx, dy int, cost float32) {
+		nx, ny := x+dx, y+dy
+		if nx < 0 || ny < 0 || nx >= w || ny >= h {
+			return
+		}
+		if v := d[ny*w+nx] + cost; v < d[y*w+x] {
+			d[y*w+x] = v
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			relax(x, y, -1, 0, straight)
+			relax(x, y, 0, -1, straight)
+			relax(x, y, -1, -1, diagonal)
+			relax(x, y, 1, -1, diagonal)
+		}
+	}
+	for y := h - 1; y >= 0; y-- {
+		for x := w - 1; x >= 0; x-- {
+			relax(x, y, 1, 0, straight)
+			relax(x, y, 0, 1, straight)
+			relax(x, y, 1, 1, diagonal)
+			relax(x, y, -1, 1, diagonal)
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 1 - d[y*w+x]/float32(spread)
+			if v < 0 {
+				v = 0
+			}
+			field.Pix[field.PixOffset(origin.X+x, origin.Y+y)] = uint8(v*255 + 0.5)
+		}
+	}
+}
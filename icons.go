@@ -0,0 +1,62 @@
+package gltext
+
+//RuneRange is an inclusive range of code points to bake into an atlas
+type RuneRange struct {
+	Low, High rune
+}
+
+//PrivateUseArea is where icon fonts such as Font Awesome and Material Icons put their glyphs
+var PrivateUseArea = RuneRange{0xE000, 0xF8FF}
+
+func expandRanges(ranges []RuneRange) []rune {
+	runes := make([]rune, 0)
+	for _, r := range ranges {
+		for ch := r.Low; ch <= r.High; ch++ {
+			runes = append(runes, ch)
+		}
+	}
+	return runes
+}
+
+//NewFontRanges bakes only the runes in the given ranges that the font contains
+func NewFontRanges(fontPath string, scale int32, dpi float64, width, height float32, ranges ...RuneRange) *Font {
+	source := newFontSource(fontPath, scale, dpi, width, height)
+	source.runes = expandRanges(ranges)
+	return uploadFont(source, generateAtlas(loadFont(fontPath), source, nil))
+}
+
+//NewIconFont is like NewFontRanges, defaulting to the private use area, but makes no assumptions about advance widths:
+//a glyph always advances at least as far as its ink extends, since icon fonts often report zero or arbitrary advances.
+func NewIconFont(fontPath string, scale int32, dpi float64, width, height float32, ranges ...RuneRange) *Font {
+	if len(ranges) == 0 {
+		ranges = []RuneRange{PrivateUseArea}
+	}
+	source := newFontSource(fontPath, scale, dpi, width, height)
+	source.runes = expandRanges(ranges)
+	source.inkAdvance = true
+	return uploadFont(source, generateAtlas(loadFont(fontPath), source, nil))
+}
+
+//GlyphInk returns the tight bounds of r's glyph relative to the x, y it is drawn at
+func (this *Font) GlyphInk(r rune) (Rect, bool) {
+	index, err := this.glyphIndex(r)
+	if err != nil {
+		return Rect{}, false
+	}
+	return this.atlas.ink[index], true
+}
+
+//CenterGlyph returns the position to draw r at so that its ink is centred in box, which suits icons whose metrics don't match their artwork
+func (this *Font) CenterGlyph(r rune, box Rect) (x, y float32) {
+	ink, ok := this.GlyphInk(r)
+	if !ok {
+		return box.X, box.Y
+	}
+	return box.X + (box.W-ink.W)/2 - ink.X, box.Y - (box.H-ink.H)/2 - ink.Y
+}
+
+//DrawIcon draws r centred in box
+func (this *Font) DrawIcon(r rune, box Rect) {
+	x, y := this.CenterGlyph(r, box)
+	this.print(x, y, string(r), this.style)
+}
@@ -0,0 +1,41 @@
+package gltext
+
+//IndexedColors is how many indexed colors a font holds
+const IndexedColors = 16
+
+//indexedColorPass tells the glyph shaders to tint each glyph by its entry of the indexed colors
+const indexedColorPass Effect = 16
+
+//SetIndexedColors replaces the colors that glyphs drawn with Style.IndexedColor pick from. only the first
+//IndexedColors are kept, and the rest of the entries are white.
+func (this *Font) SetIndexedColors(colors []Vector4) {
+	if len(colors) > IndexedColors {
+		colors = colors[:IndexedColors]
+	}
+	this.indexedColors = append([]Vector4(nil), colors...)
+	this.uploadIndexedColors()
+}
+
+func (this *Font) uploadIndexedColors() {
+	values := make([]float32, 0, 4*IndexedColors)
+	for i := 0; i < IndexedColors; i++ {
+		c := Vector4{1, 1, 1, 1}
+		if i < len(this.indexedColors) {
+			c = this.indexedColors[i]
+		}
+		values = append(values, c[:]...)
+	}
+	this.program.Use()
+	this.program.GetUniformLocation("indexedColors").Uniform4fv(IndexedColors, values)
+	this.program.Unuse()
+}
+
+//SetColorIndex has the runes in [start, end) drawn in indexed color index, when the layout is drawn with IndexedColor
+func (this *Layout) SetColorIndex(start, end, index int) {
+	if index < 0 || index >= IndexedColors {
+		return
+	}
+	for g := maxInt(start, 0); g < end && g < len(this.glyphs); g++ {
+		this.glyphs[g].colorIndex = uint8(index)
+	}
+}
@@ -0,0 +1,77 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"image"
+	"image/draw"
+)
+
+//ObjectReplacement marks where an Inline element goes in a string passed to LayoutInline
+const ObjectReplacement = '\uFFFC'
+
+//Inline is a non-glyph element, such as an item icon, that takes part in layout like a character.
+//its bottom edge sits Descent below the baseline; a taller element extends above the line rather than pushing lines apart.
+type Inline struct {
+	Icon          *Icon
+	Width, Height float32 //in draw units
+	Descent       float32
+}
+
+//Icon is an image, or a region of an existing texture, that can be drawn inline with text
+type Icon struct {
+	texture gl.Texture
+	uv      Vector4
+	owned   bool
+}
+
+//NewIcon uploads img as its own texture; it must be called on the GL thread
+func NewIcon(img image.Image) *Icon {
+	tex, _, _ := uploadImage(img)
+	return &Icon{texture: tex, uv: Vector4{0, 0, 1, 1}, owned: true}
+}
+
+//NewAtlasIcon refers to the region u1, v1 - u2, v2 of a texture the caller owns, such as a sprite sheet
+func NewAtlasIcon(texture gl.Texture, u1, v1, u2, v2 float32) *Icon {
+	return &Icon{texture: texture, uv: Vector4{u1, v1, u2, v2}}
+}
+
+//Delete frees the icon's texture if NewIcon created it
+func (this *Icon) Delete() {
+	if this.owned {
+		this.texture.Delete()
+	}
+}
+
+func uploadImage(img image.Image) (tex gl.Texture, width, height int) {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	tex = gl.GenTexture()
+	tex.Bind(gl.TEXTURE_2D)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, rgba.Bounds().Dx(), rgba.Bounds().Dy(), 0, gl.RGBA, gl.UNSIGNED_BYTE, rgba.Pix)
+	tex.Unbind(gl.TEXTURE_2D)
+	return tex, rgba.Bounds().Dx(), rgba.Bounds().Dy()
+}
+
+func (this *Layout) drawInlines(alpha float32) {
+	baseline := this.baseline()
+	for _, g := range this.glyphs {
+		if g.inline == nil || g.inline.Icon == nil {
+			continue
+		}
+		in := g.inline
+		bottom := g.y - baseline - in.Descent
+		this.font.rects.drawQuad(quad{
+			rect:    Rect{g.x, bottom + in.Height, in.Width, in.Height},
+			color:   Vector4{1, 1, 1, alpha},
+			texture: in.Icon.texture,
+			uv:      in.Icon.uv,
+		})
+	}
+}
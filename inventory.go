@@ -0,0 +1,60 @@
+package gltext
+
+//DrawnText is a string the font drew while keeping a text inventory, and where it was drawn
+type DrawnText struct {
+	Text   string
+	Rect   Rect //in draw units, measured by advance like Layout.Rect
+	Pixels Rect //in window pixels from the top left, with Y the top edge
+}
+
+//TextInventory is every string a font drew, in the order they were drawn, for UI automation tests and screen readers
+type TextInventory []DrawnText
+
+//Find returns the first string drawn that is exactly text
+func (this TextInventory) Find(text string) (DrawnText, bool) {
+	for _, d := range this {
+		if d.Text == text {
+			return d, true
+		}
+	}
+	return DrawnText{}, false
+}
+
+//At returns the last string drawn, and so the topmost, whose rectangle contains the point x, y in draw units
+func (this TextInventory) At(x, y float32) (DrawnText, bool) {
+	for i := len(this) - 1; i >= 0; i-- {
+		if this[i].Rect.Contains(x, y) {
+			return this[i], true
+		}
+	}
+	return DrawnText{}, false
+}
+
+//SetTextInventory starts or stops recording every string the font draws. it costs a string and a rectangle for each
+//one, so leave it off unless something reads the inventory.
+func (this *Font) SetTextInventory(on bool) {
+	this.inventoryOn = on
+	this.inventory = nil
+}
+
+//TakeTextInventory returns what has been drawn since it was last called, and starts the next frame's inventory afresh
+func (this *Font) TakeTextInventory() TextInventory {
+	inventory := this.inventory
+	this.inventory = nil
+	return inventory
+}
+
+//recordText adds s drawn over r to the inventory
+func (this *Font) recordText(s string, r Rect) {
+	w, h := this.source.width/2, this.source.height/2
+	this.inventory = append(this.inventory, DrawnText{s, r, Rect{r.X * w, -r.Y * h, r.W * w, r.H * h}})
+}
+
+//text returns the runes the layout was made from
+func (this *Layout) text() string {
+	runes := make([]rune, len(this.glyphs))
+	for i, g := range this.glyphs {
+		runes[i] = g.r
+	}
+	return string(runes)
+}
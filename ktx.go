@@ -0,0 +1,255 @@
+package gltext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/jimarnold/gl"
+	"io"
+	"io/ioutil"
+)
+
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+//VkFormat values of the formats an atlas page can be stored in
+const (
+	vkR8G8B8A8Unorm    = 37
+	vkBC4UnormBlock    = 139
+	vkEACR11UnormBlock = 153
+)
+
+//ktx2GlyphsKey names the key/value entry recording the cell size and runes a page was baked with,
+//so that it can't be loaded into an atlas laid out differently
+const ktx2GlyphsKey = "gltext.glyphs"
+
+type ktx2Header struct {
+	Format, TypeSize                           uint32
+	Width, Height, Depth, Layers, Faces        uint32
+	Levels, Supercompression                   uint32
+	DFDOffset, DFDLength, KVDOffset, KVDLength uint32
+	SGDOffset, SGDLength                       uint64
+}
+
+type ktx2Level struct {
+	Offset, Length, UncompressedLength uint64
+}
+
+//WriteAtlasKTX2 writes atlas page p to w as a KTX2 file, in the atlas compression or as premultiplied RGBA8 without it
+func (this *Font) WriteAtlasKTX2(w io.Writer, p int) error {
+	a := this.atlas
+	if p < 0 || p >= len(a.pages) {
+		return fmt.Errorf("gltext: atlas has no page %d", p)
+	}
+	img := a.pages[p]
+	format, data, align := uint32(vkR8G8B8A8Unorm), img.Pix, 4
+	if this.compression != CompressNone {
+		format, data, align = this.compression.vkFormat(), compressRegion(img, img.Bounds(), this.compression), 8
+	}
+
+	dfd := ktx2DataFormat(format)
+	kvd := append(ktx2KeyValue("KTXwriter", "gltext"), ktx2KeyValue(ktx2GlyphsKey, this.pageGlyphs(p))...)
+	header := ktx2Header{
+		Format:    format,
+		TypeSize:  1,
+		Width:     uint32(img.Bounds().Dx()),
+		Height:    uint32(img.Bounds().Dy()),
+		Faces:     1,
+		Levels:    1,
+		DFDOffset: uint32(len(ktx2Identifier) + binary.Size(ktx2Header{}) + binary.Size(ktx2Level{})),
+		DFDLength: uint32(len(dfd)),
+		KVDLength: uint32(len(kvd)),
+	}
+	header.KVDOffset = header.DFDOffset + header.DFDLength
+	end := int(header.KVDOffset + header.KVDLength)
+	padding := (align - end%align) % align
+	level := ktx2Level{uint64(end + padding), uint64(len(data)), uint64(len(data))}
+
+	var b bytes.Buffer
+	b.Write(ktx2Identifier)
+	binary.Write(&b, binary.LittleEndian, header)
+	binary.Write(&b, binary.LittleEndian, level)
+	b.Write(dfd)
+	b.Write(kvd)
+	b.Write(make([]byte, padding))
+	b.Write(data)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+//ReadAtlasKTX2 loads a KTX2 file into atlas page p, for textures compressed and mipmapped by offline tools.
+//the file must be the size of the page and hold premultiplied RGBA8, BC4 or EAC R11, without supercompression; files
+//written by WriteAtlasKTX2 must also come from an atlas with the same glyphs. the atlas takes on the file's compression.
+//mipmaps are kept until the page is next uploaded in full, for instance when the atlas grows.
+func (this *Font) ReadAtlasKTX2(r io.Reader, p int) error {
+	a := this.atlas
+	if p < 0 || p >= len(a.pages) {
+		return fmt.Errorf("gltext: atlas has no page %d", p)
+	}
+	file, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(file) < len(ktx2Identifier) || !bytes.Equal(file[:len(ktx2Identifier)], ktx2Identifier) {
+		return ErrKTX2Unsupported
+	}
+	in := bytes.NewReader(file[len(ktx2Identifier):])
+	var header ktx2Header
+	if err := binary.Read(in, binary.LittleEndian, &header); err != nil {
+		return ErrKTX2Unsupported
+	}
+	levels := make([]ktx2Level, maxInt(int(header.Levels), 1))
+	if err := binary.Read(in, binary.LittleEndian, levels); err != nil {
+		return ErrKTX2Unsupported
+	}
+	c, ok := compressionOf(header.Format)
+	if !ok || header.Supercompression != 0 || header.Depth > 1 || header.Layers > 1 || header.Faces != 1 {
+		return ErrKTX2Unsupported
+	}
+	if c == CompressETC2R && !glVersionAtLeast(4, 3) {
+		return ErrCompressionUnsupported
+	}
+	img := a.pages[p]
+	if int(header.Width) != img.Bounds().Dx() || int(header.Height) != img.Bounds().Dy() {
+		return ErrAtlasMismatch
+	}
+	if uint64(header.KVDOffset)+uint64(header.KVDLength) > uint64(len(file)) {
+		return ErrKTX2Unsupported
+	}
+	if glyphs, ok := ktx2Lookup(file[header.KVDOffset:header.KVDOffset+header.KVDLength], ktx2GlyphsKey); ok && glyphs != this.pageGlyphs(p) {
+		return ErrAtlasMismatch
+	}
+	data := make([][]byte, len(levels))
+	for i, level := range levels {
+		if level.Offset+level.Length > uint64(len(file)) {
+			return ErrKTX2Unsupported
+		}
+		data[i] = file[level.Offset : level.Offset+level.Length]
+	}
+	if c == CompressNone && len(data[0]) < len(img.Pix) {
+		return ErrKTX2Unsupported
+	}
+
+	//keep the CPU copy in step, for Image, halos and glyphs loaded later
+	if c == CompressNone {
+		copy(img.Pix, data[0])
+	} else {
+		decompressRegion(img, img.Bounds(), data[0], c)
+	}
+	if c != this.compression {
+		this.setCompression(c)
+		this.uploadAtlas()
+	}
+
+	if this.dsa {
+		this.readPageDirect(p, c, data)
+		this.atlasChanged()
+		return nil
+	}
+	this.textures[p].Bind(gl.TEXTURE_2D)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	for i, level := range data {
+		w, h := maxInt(img.Bounds().Dx()>>uint(i), 1), maxInt(img.Bounds().Dy()>>uint(i), 1)
+		if c == CompressNone {
+			gl.TexImage2D(gl.TEXTURE_2D, i, gl.RGBA, w, h, 0, gl.RGBA, gl.UNSIGNED_BYTE, level)
+		} else {
+			gl.CompressedTexImage2D(gl.TEXTURE_2D, i, c.internalFormat(), w, h, 0, len(level), level)
+		}
+	}
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, len(data)-1)
+	if len(data) > 1 {
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+	}
+	this.atlasChanged()
+	return nil
+}
+
+func (this AtlasCompression) vkFormat() uint32 {
+	switch this {
+	case CompressBC4:
+		return vkBC4UnormBlock
+	case CompressETC2R:
+		return vkEACR11UnormBlock
+	}
+	return vkR8G8B8A8Unorm
+}
+
+func compressionOf(vkFormat uint32) (AtlasCompression, bool) {
+	switch vkFormat {
+	case vkR8G8B8A8Unorm:
+		return CompressNone, true
+	case vkBC4UnormBlock:
+		return CompressBC4, true
+	case vkEACR11UnormBlock:
+		return CompressETC2R, true
+	}
+	return CompressNone, false
+}
+
+//pageGlyphs describes the layout of page p: its cell size and the runes of its glyphs in order
+func (this *Font) pageGlyphs(p int) string {
+	a := this.atlas
+	first := p * a.pageCells()
+	last := minInt(first+a.pageCells(), len(a.glyphs))
+	return fmt.Sprintf("%dx%d %s", a.cellWidth, a.cellPixels, string(a.glyphs[first:last]))
+}
+
+//ktx2DataFormat builds the basic data format descriptor of one of the atlas formats
+func ktx2DataFormat(format uint32) []byte {
+	type sample struct {
+		offset  uint16
+		length  uint8 //in bits, less one
+		channel uint8
+		upper   uint32
+	}
+	model, flags, block, plane := uint8(1), uint8(1), [4]uint8{}, uint8(4) //RGBSDA with premultiplied alpha
+	samples := []sample{{0, 7, 0, 255}, {8, 7, 1, 255}, {16, 7, 2, 255}, {24, 7, 15, 255}}
+	if format != vkR8G8B8A8Unorm {
+		model, flags, block, plane = 131, 0, [4]uint8{3, 3}, 8 //BC4
+		if format == vkEACR11UnormBlock {
+			model = 161 //ETC2
+		}
+		samples = []sample{{0, 63, 0, 0xFFFFFFFF}}
+	}
+
+	var b bytes.Buffer
+	put := func(v interface{}) { binary.Write(&b, binary.LittleEndian, v) }
+	put(uint32(4 + 24 + 16*len(samples)))
+	put(uint32(0))                    //Khronos vendor, basic descriptor type
+	put(uint16(2))                    //version
+	put(uint16(24 + 16*len(samples))) //block size
+	put([4]uint8{model, 1, 1, flags}) //BT.709 primaries, linear transfer
+	put(block)                        //texel block dimensions, less one
+	put([8]uint8{plane})              //bytes per plane
+	for _, s := range samples {
+		put(s.offset)
+		put([2]uint8{s.length, s.channel})
+		put([4]uint8{}) //sample position
+		put([2]uint32{0, s.upper})
+	}
+	return b.Bytes()
+}
+
+//ktx2KeyValue encodes an entry of the key/value data, padded to four bytes
+func ktx2KeyValue(key, value string) []byte {
+	entry := append(append([]byte(key), 0), value...)
+	b := make([]byte, 4, 4+len(entry)+3)
+	binary.LittleEndian.PutUint32(b, uint32(len(entry)))
+	b = append(b, entry...)
+	return append(b, make([]byte, (4-len(entry)%4)%4)...)
+}
+
+//ktx2Lookup finds the value of key in key/value data
+func ktx2Lookup(kvd []byte, key string) (string, bool) {
+	for len(kvd) >= 4 {
+		n := int(binary.LittleEndian.Uint32(kvd))
+		if 4+n > len(kvd) {
+			break
+		}
+		entry := kvd[4 : 4+n]
+		if i := bytes.IndexByte(entry, 0); i >= 0 && string(entry[:i]) == key {
+			return string(bytes.TrimRight(entry[i+1:], "\x00")), true
+		}
+		kvd = kvd[minInt(4+n+(4-n%4)%4, len(kvd)):]
+	}
+	return "", false
+}
@@ -0,0 +1,522 @@
+package gltext
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+//Layout is a string positioned and measured by a Font, ready to be drawn or queried.
+//Lines are separated by '\n', "\r\n", '\r', LineSeparator or ParagraphSeparator and each is one cell height below the last.
+type Layout struct {
+	font   *Font
+	style  Style
+	x, y   float32
+	glyphs []layoutGlyph
+	lines  []layoutLine
+	scale  float32    //set by Scale; zero is full size
+	alpha  float32    //set by SetAlpha
+	runs   []glyphRun //the layout's span of the font's batch, as of the last draw
+
+	highlights []highlight
+	spans      []Span //set by SetSpans
+	links      []link
+	hovered    int //index into links, or -1
+	LinkStyle  LinkStyle
+	OnLink     func(tag string)
+	err        error
+}
+
+//highlight colors the background of the runes in [start, end)
+type highlight struct {
+	start, end int
+	color      Vector4
+	span       bool //the background of a Span, replaced by the next SetSpans
+}
+
+//layoutGlyph is one rune of the laid out string
+type layoutGlyph struct {
+	r          rune
+	index      int //atlas index, or -1 when the font cannot draw r
+	x, y       float32
+	advance    float32
+	byteOffset int
+	line       int
+	color      *Vector4 //overrides the style's color when set
+	colorIndex uint8    //the glyph's indexed color, for Style.IndexedColor
+	scale      float32  //size relative to the font, or zero for full size
+	inset      float32  //distance the glyph is drawn to the right of x
+	angle      float32  //radians the glyph is turned counterclockwise about its baseline origin
+	inline     *Inline
+}
+
+//layoutLine covers glyphs[first:end], including the terminating newline if any
+type layoutLine struct {
+	first, end int
+	x, y       float32
+	width      float32
+}
+
+func (this *Font) Layout(x, y float32, fs string, argv ...interface{}) *Layout {
+	return this.LayoutStyle(x, y, this.style, this.sprintf(fs, argv...))
+}
+
+func (this *Font) LayoutStyle(x, y float32, style Style, s string) *Layout {
+	return this.LayoutInline(x, y, style, s, nil)
+}
+
+//LayoutInline lays out s with each ObjectReplacement rune standing in for the next element of inlines, in order
+func (this *Font) LayoutInline(x, y float32, style Style, s string, inlines []Inline) *Layout {
+	return this.layoutText(x, y, 0, style, s, inlines)
+}
+
+//LayoutWrapped breaks lines at spaces so that none is wider than maxWidth, and applies the style's paragraph options.
+//every line break other than LineSeparator starts a new paragraph.
+func (this *Font) LayoutWrapped(x, y, maxWidth float32, style Style, s string) *Layout {
+	return this.layoutText(x, y, maxWidth, style, s, nil)
+}
+
+//layoutBuilder holds the state of a layout while it is being built
+type layoutBuilder struct {
+	font     *Font
+	style    Style
+	maxWidth float32 //zero for no wrapping
+	layout   *Layout
+	line     layoutLine
+	penX     float32
+	tab      pendingTab
+	breakAt  int           //first glyph after the last space on the current line, or -1
+	shaped   []shapedGlyph //what is left of the segment being laid out
+}
+
+func (this *Font) layoutText(x, y, maxWidth float32, style Style, s string, inlines []Inline) *Layout {
+	defer endTrace(beginTrace(PhaseLayout))
+	//evicting a glyph the layout already uses would leave it drawing another
+	if a := this.atlas; !a.pinned {
+		a.pinned, a.pinnedAt = true, a.clock
+		defer func() { a.pinned = false }()
+	}
+	if style.Normalize && !norm.NFC.IsNormalString(s) {
+		s = norm.NFC.String(s)
+	}
+	b := &layoutBuilder{
+		font:     this,
+		style:    style,
+		maxWidth: maxWidth,
+		layout:   &Layout{font: this, style: style, x: x, y: y, alpha: 1, hovered: -1, LinkStyle: DefaultLinkStyle()},
+		tab:      pendingTab{start: -1},
+	}
+	paragraph := style.Paragraph
+	b.startLine(y, x+paragraph.FirstLineIndent)
+
+	for i, ch := range s {
+		brk, paragraphBreak := breakAt(s, i, ch)
+		if ch == '\t' || brk {
+			b.endTab()
+		}
+		g := layoutGlyph{r: ch, index: -1, byteOffset: i}
+		if ch == ObjectReplacement && len(inlines) > 0 {
+			g.inline = &inlines[0]
+			g.advance = inlines[0].Width + style.Tracking
+			inlines = inlines[1:]
+		} else if ch == '\t' {
+			stop := style.tabStop(b.penX-b.line.x, this.tabWidth())
+			g.advance = b.line.x + stop.Position - b.penX
+			b.tab = pendingTab{stop: stop, lineX: b.line.x, tab: len(b.layout.glyphs), start: len(b.layout.glyphs) + 1}
+		} else {
+			if len(b.shaped) == 0 {
+				b.shaped = this.shape(nextSegment(s[i:]), style)
+			}
+			shaped := b.shaped[0]
+			b.shaped = b.shaped[1:]
+			if isLineBreak(ch) {
+				shaped = shapedGlyph{index: -1}
+			} else if isLayoutControl(ch) {
+				shaped = b.control(ch, i)
+			}
+			if shaped.missing && this.missingGlyph != nil {
+				shaped = this.substituteGlyph(MissingGlyph{ch, s, i}, style)
+			}
+			g.index, g.advance, g.scale, g.inset = shaped.index, shaped.advance, shaped.scale, shaped.inset
+		}
+
+		if maxWidth > 0 && ch != ' ' && !brk && g.advance > 0 && b.penX+g.advance > x+maxWidth {
+			b.wrap()
+		}
+		g.x, g.y, g.line = b.penX, b.line.y, len(b.layout.lines)
+		b.layout.glyphs = append(b.layout.glyphs, g)
+		b.penX += g.advance
+
+		switch {
+		case ch == ' ', ch == ZeroWidthSpace:
+			b.breakAt = len(b.layout.glyphs)
+		case paragraphBreak:
+			b.endLine(len(b.layout.glyphs), b.penX-b.line.x)
+			b.startLine(b.line.y-this.cellHeight-paragraph.SpaceAfter-paragraph.SpaceBefore, x+paragraph.FirstLineIndent)
+		case brk:
+			b.endLine(len(b.layout.glyphs), b.penX-b.line.x)
+			b.startLine(b.line.y-this.cellHeight, x+paragraph.HangingIndent)
+		}
+	}
+	b.endTab()
+	b.endLine(len(b.layout.glyphs), b.penX-b.line.x)
+	b.layout.alignVertically(style.VerticalAlign)
+	return b.layout
+}
+
+func (this *layoutBuilder) startLine(y, x float32) {
+	this.line = layoutLine{first: len(this.layout.glyphs), x: x, y: y}
+	this.penX = x
+	this.breakAt = -1
+}
+
+func (this *layoutBuilder) endLine(end int, width float32) {
+	this.line.end = end
+	this.line.width = width
+	this.layout.lines = append(this.layout.lines, this.line)
+}
+
+func (this *layoutBuilder) endTab() {
+	this.penX = this.layout.alignTab(this.tab, this.penX)
+	this.tab.start = -1
+}
+
+//wrap ends the current line at the last space, or before the next glyph if the line has no spaces, and carries what follows on to a new line
+func (this *layoutBuilder) wrap() {
+	glyphs := this.layout.glyphs
+	at := this.breakAt
+	if at <= this.line.first {
+		at = len(glyphs)
+		if at == this.line.first {
+			return
+		}
+	}
+	//trailing spaces hang off the end of the line and don't count towards its width
+	last := at
+	for last > this.line.first && glyphs[last-1].r == ' ' {
+		last--
+	}
+	width := this.penX - this.line.x
+	if last < len(glyphs) {
+		width = glyphs[last].x - this.line.x
+	}
+	this.endLine(at, width)
+
+	moveFrom := this.penX
+	if at < len(glyphs) {
+		moveFrom = glyphs[at].x
+	}
+	carried := this.penX - moveFrom
+	this.startLine(this.line.y-this.font.cellHeight, this.layout.x+this.style.Paragraph.HangingIndent)
+	shift := this.line.x - moveFrom
+	for i := at; i < len(glyphs); i++ {
+		glyphs[i].x += shift
+		glyphs[i].y = this.line.y
+		glyphs[i].line = len(this.layout.lines)
+	}
+	this.line.first = at
+	this.penX = this.line.x + carried
+	//a tab whose text has been carried over can no longer be aligned
+	this.tab.start = -1
+}
+
+func (this *Layout) lineHeight() float32 {
+	return this.font.cellHeight * this.sizeScale()
+}
+
+//baseline is the distance from the top of a line to its baseline
+func (this *Layout) baseline() float32 {
+	return this.font.baseline * this.sizeScale()
+}
+
+func (this *Layout) sizeScale() float32 {
+	if this.scale == 0 {
+		return 1
+	}
+	return this.scale
+}
+
+//Width is the distance from the layout's origin to the end of its longest line
+func (this *Layout) Width() float32 {
+	width := float32(0)
+	for _, line := range this.lines {
+		if w := line.x - this.x + line.width; w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+func (this *Layout) Height() float32 {
+	last := this.lines[len(this.lines)-1]
+	return this.y - last.y + this.lineHeight()
+}
+
+//Rect is the area covered by the layout's lines, measured by advance
+func (this *Layout) Rect() Rect {
+	return Rect{this.x, this.y, this.Width(), this.Height()}
+}
+
+func (this *Layout) drawBackground(background Background) {
+	r := this.Rect()
+	pad := background.Padding
+	r = Rect{r.X - pad, r.Y + pad, r.W + 2*pad, r.H + 2*pad}
+	switch {
+	case background.NinePatch != nil:
+		this.font.drawNinePatch(background.NinePatch, r, background.Color)
+	case background.Radius > 0:
+		this.font.fillRoundedRect(r, background.Radius, background.Color)
+	default:
+		this.font.rects.draw(r.X, r.Y, r.W, r.H, background.Color)
+	}
+}
+
+//Scale resizes the layout by s about its origin, as if it had been laid out with a font s times the size
+func (this *Layout) Scale(s float32) *Layout {
+	baseline := this.font.baseline
+	for i := range this.glyphs {
+		g := &this.glyphs[i]
+		g.x = this.x + (g.x-this.x)*s
+		g.y = this.y + (g.y-baseline-this.y)*s + baseline
+		g.advance *= s
+		g.inset *= s
+		g.scale = g.drawScale() * s
+	}
+	for i := range this.lines {
+		line := &this.lines[i]
+		line.x = this.x + (line.x-this.x)*s
+		line.y = this.y + (line.y-this.y)*s
+		line.width *= s
+	}
+	this.scale = this.sizeScale() * s
+	return this
+}
+
+//MoveTo repositions the layout so that its origin is at x, y
+func (this *Layout) MoveTo(x, y float32) *Layout {
+	dx, dy := x-this.x, y-this.y
+	for i := range this.glyphs {
+		this.glyphs[i].x += dx
+		this.glyphs[i].y += dy
+	}
+	for i := range this.lines {
+		this.lines[i].x += dx
+		this.lines[i].y += dy
+	}
+	this.x, this.y = x, y
+	return this
+}
+
+//HitTest returns the index of the rune whose cell contains the point x, y
+func (this *Layout) HitTest(x, y float32) (runeIndex int, ok bool) {
+	for _, line := range this.lines {
+		if y > line.y || y <= line.y-this.lineHeight() {
+			continue
+		}
+		for i := line.first; i < line.end; i++ {
+			g := this.glyphs[i]
+			if x >= g.x && x < g.x+g.advance {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+//CaretPos returns the top of the insertion point before the rune at index; an index of the rune count or more places it at the end of the text
+func (this *Layout) CaretPos(index int) (x, y, height float32) {
+	if index < 0 {
+		index = 0
+	}
+	if index < len(this.glyphs) {
+		g := this.glyphs[index]
+		return g.x, g.y, this.lineHeight()
+	}
+	last := this.lines[len(this.lines)-1]
+	return last.x + last.width, last.y, this.lineHeight()
+}
+
+//clipX hides glyphs that do not fit entirely between minX and maxX
+func (this *Layout) clipX(minX, maxX float32) {
+	for i, g := range this.glyphs {
+		if g.x < minX || g.x+g.advance > maxX {
+			this.glyphs[i].index = -1
+		}
+	}
+}
+
+//PixelToDraw converts a window position in pixels, measured from the top left, into the units used by Printf and Layout
+func (this *Font) PixelToDraw(px, py float32) (x, y float32) {
+	return px * 2 / this.source.width, -py * 2 / this.source.height
+}
+
+func (this *Layout) Draw() {
+	this.DrawAlpha(1)
+}
+
+//DrawAlpha draws with the opacity of the layout's style multiplied by alpha, and by the layout's own
+func (this *Layout) DrawAlpha(alpha float32) {
+	alpha *= this.alpha
+	if alpha <= 0 {
+		return
+	}
+	if alpha == 1 {
+		this.draw(this.style, 1)
+		return
+	}
+	this.draw(this.style.withAlpha(alpha), alpha)
+}
+
+//SetAlpha multiplies the opacity of every color the layout is drawn in by alpha, leaving the colors of its style as
+//they are, so fading it needs no new style each frame
+func (this *Layout) SetAlpha(alpha float32) *Layout {
+	this.alpha = alpha
+	return this
+}
+
+func (this *Layout) Alpha() float32 {
+	return this.alpha
+}
+
+func (this *Layout) draw(style Style, alpha float32) {
+	style = this.font.themed(style, alpha)
+	restore := this.font.applyClip()
+	defer restore()
+	this.upload()
+	this.drawUnder(style, alpha)
+	this.queuePasses(style, alpha)
+	this.font.flushGlyphs()
+	this.drawOver(style, alpha)
+}
+
+//drawLayouts draws layouts of the font together, each in its style with its opacity scaled by its alpha. their glyphs go
+//up in one batch and are drawn in one flush, over all of their backgrounds and under all of their decorations.
+//layouts with a halo are drawn one at a time, since the halo's width is not part of the DrawParams.
+func (this *Font) drawLayouts(layouts []*Layout, styles []Style, alphas []float32) {
+	restore := this.applyClip()
+	defer restore()
+	together := make([]int, 0, len(layouts))
+	for i, l := range layouts {
+		styles[i] = this.themed(styles[i], alphas[i])
+		if halo := styles[i].Halo; halo.Width > 0 && halo.Color[3] > 0 {
+			l.draw(styles[i], alphas[i])
+		} else {
+			together = append(together, i)
+		}
+	}
+	if len(together) == 0 {
+		return
+	}
+	this.beginBatch()
+	for _, i := range together {
+		layouts[i].batch()
+	}
+	this.uploadBatch()
+	for _, i := range together {
+		layouts[i].drawUnder(styles[i], alphas[i])
+	}
+	for _, i := range together {
+		layouts[i].queuePasses(styles[i], alphas[i])
+	}
+	this.flushGlyphs()
+	for _, i := range together {
+		layouts[i].drawOver(styles[i], alphas[i])
+	}
+}
+
+//drawUnder draws the background and highlights
+func (this *Layout) drawUnder(style Style, alpha float32) {
+	if style.Background.Color[3] > 0 {
+		this.drawBackground(style.Background)
+	}
+	for _, h := range this.highlights {
+		color := h.color
+		color[3] *= alpha
+		for _, r := range this.SelectionRects(h.start, h.end) {
+			this.font.rects.draw(r.X, r.Y, r.W, r.H, color)
+		}
+	}
+}
+
+//queuePasses queues the glyph passes of style, from the halo up, for the next flush
+func (this *Layout) queuePasses(style Style, alpha float32) {
+	//shadows and outlines move with the glyphs but keep their own color
+	motion := style.Animation.Effects &^ Rainbow
+	if style.Halo.Width > 0 && style.Halo.Color[3] > 0 {
+		this.drawHalo(style.Halo, motion, alpha)
+	}
+	if style.Shadow.Color[3] > 0 {
+		this.drawGlyphs(style.Shadow.Offset[0], style.Shadow.Offset[1], style.Shadow.Color, motion, false, alpha)
+	}
+	if style.Outline.Width > 0 {
+		//without a distance field the outline is approximated by stamping the string around a ring
+		for _, d := range outlineDirections {
+			this.drawGlyphs(d[0]*style.Outline.Width, d[1]*style.Outline.Width, style.Outline.Color, motion, false, alpha)
+		}
+	}
+	effects := style.Animation.Effects
+	if style.IndexedColor {
+		effects |= indexedColorPass
+	}
+	this.drawGlyphs(0, 0, style.Color, effects, true, alpha)
+}
+
+//drawOver draws what goes over the glyphs: inline images, decorations, those of spans and links
+func (this *Layout) drawOver(style Style, alpha float32) {
+	this.drawInlines(alpha)
+	this.drawDecorations(style)
+	this.drawSpanDecorations(style, alpha)
+	this.drawLinkDecorations(alpha)
+}
+
+//upload puts the layout's glyphs in the font's batch, which every pass of draw then draws from
+func (this *Layout) upload() {
+	this.font.beginBatch()
+	this.batch()
+	this.font.uploadBatch()
+}
+
+//batch adds the layout's glyphs to the font's batch in runs of their own
+func (this *Layout) batch() {
+	font := this.font
+	font.stats.Strings++
+	if font.inventoryOn {
+		font.recordText(this.text(), this.Rect())
+	}
+	font.splitRuns()
+	first := len(font.runs)
+	for i, g := range this.glyphs {
+		if g.index >= 0 {
+			font.batchGlyph(i, g.index, g.x+g.inset, g.y, g.drawScale(), g.angle, g.color, int(g.colorIndex))
+		}
+	}
+	this.runs = font.runs[first:]
+}
+
+//drawGlyphs queues a pass over every glyph in color, or in its own color if perGlyph is set and it has one
+func (this *Layout) drawGlyphs(dx, dy float32, color Vector4, effects Effect, perGlyph bool, alpha float32) {
+	this.font.queueGlyphs(this.runs, color, dx, dy, effects, this.style.Animation, perGlyph, effects&haloPass != 0, alpha)
+}
+
+func (this *Layout) drawDecorations(style Style) {
+	for _, r := range this.decorationRects(style) {
+		this.font.rects.draw(r.X, r.Y, r.W, r.H, style.Color)
+	}
+}
+
+//decorationRects returns the underlines and strikethroughs of every line
+func (this *Layout) decorationRects(style Style) []Rect {
+	if style.Decorations == 0 {
+		return nil
+	}
+	rects := make([]Rect, 0)
+	baseline := this.baseline()
+	thickness := baseline / 12
+	for _, line := range this.lines {
+		if style.Decorations&Underline != 0 {
+			rects = append(rects, Rect{line.x, line.y - baseline - thickness, line.width, thickness})
+		}
+		if style.Decorations&Strikethrough != 0 {
+			rects = append(rects, Rect{line.x, line.y - baseline*0.65, line.width, thickness})
+		}
+	}
+	return rects
+}
@@ -0,0 +1,90 @@
+package gltext
+
+//LayoutCacheSize bounds how many layouts each font keeps for strings drawn with Printf and PrintfStyle,
+//so that the same menu labels and captions drawn every frame are only measured once. zero disables the cache.
+var LayoutCacheSize = 256
+
+//layoutKey is everything that decides where glyphs go; colors and animation are applied when drawing
+type layoutKey struct {
+	text           string
+	maxWidth       float32
+	tracking       float32
+	paragraph      Paragraph
+	transform      Transform
+	ligatures      bool
+	tabularFigures bool
+	normalize      bool
+	controls       ControlPolicy
+	verticalAlign  VerticalAlign
+}
+
+//layoutCache keeps two generations of layouts, laid out at the origin: once the current one is full it becomes the
+//previous one, and layouts used from the previous generation are carried forward, so whatever is drawn each frame stays
+type layoutCache struct {
+	current, previous map[layoutKey]*cachedLayout
+}
+
+type cachedLayout struct {
+	layout *Layout
+	x, y   float32 //where the layout's origin was when first built at 0, 0, which vertical alignment may have moved
+}
+
+func newLayoutKey(s string, maxWidth float32, style Style) (layoutKey, bool) {
+	if len(style.TabStops) > 0 {
+		return layoutKey{}, false
+	}
+	return layoutKey{
+		text:           s,
+		maxWidth:       maxWidth,
+		tracking:       style.Tracking,
+		paragraph:      style.Paragraph,
+		transform:      style.Transform,
+		ligatures:      style.Ligatures,
+		tabularFigures: style.TabularFigures,
+		normalize:      style.Normalize,
+		controls:       style.Controls,
+		verticalAlign:  style.VerticalAlign,
+	}, true
+}
+
+//cachedLayout is layoutText for layouts the caller won't keep or modify beyond drawing them
+func (this *Font) cachedLayout(x, y, maxWidth float32, style Style, s string) *Layout {
+	key, ok := newLayoutKey(s, maxWidth, style)
+	if !ok || LayoutCacheSize <= 0 {
+		return this.layoutText(x, y, maxWidth, style, s, nil)
+	}
+	c := &this.layouts
+	cached, found := c.current[key]
+	if !found {
+		keep := true
+		if cached, found = c.previous[key]; !found {
+			this.stats.LayoutCacheMisses++
+			generation := this.generation
+			layout := this.layoutText(0, 0, maxWidth, style, s, nil)
+			cached = &cachedLayout{layout, layout.x, layout.y}
+			//glyphs were evicted while it was laid out, so it belongs to no generation of the cache
+			keep = this.generation == generation
+		}
+		if keep {
+			if len(c.current) >= LayoutCacheSize {
+				c.previous, c.current = c.current, nil
+			}
+			if c.current == nil {
+				c.current = make(map[layoutKey]*cachedLayout)
+			}
+			c.current[key] = cached
+		}
+	}
+	if found {
+		this.stats.LayoutCacheHits++
+	}
+	layout := cached.layout
+	layout.MoveTo(x+cached.x, y+cached.y)
+	layout.style = style
+	return layout
+}
+
+//clear forgets every layout, for when glyph metrics change
+func (this *layoutCache) clear() {
+	this.current, this.previous = make(map[layoutKey]*cachedLayout), nil
+}
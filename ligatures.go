@@ -0,0 +1,40 @@
+package gltext
+
+import (
+	"strings"
+)
+
+//standardLigatures are matched longest first. Without a shaping engine to read the font's own ligature tables,
+//gltext substitutes the Unicode presentation forms, which most text fonts provide.
+var standardLigatures = []struct {
+	text string
+	r    rune
+}{
+	{"ffi", 0xFB03},
+	{"ffl", 0xFB04},
+	{"ff", 0xFB00},
+	{"fi", 0xFB01},
+	{"fl", 0xFB02},
+}
+
+type pendingLigature struct {
+	remaining int //runes still to be covered by the current ligature
+	share     float32
+}
+
+//ligatureAt returns the glyph for a ligature at the start of s and the number of runes it replaces.
+//ligatures are skipped when tracking or a case transform would make them look wrong.
+func (this *Font) ligatureAt(s string, style Style) (index, runes int, ok bool) {
+	if !style.Ligatures || style.Tracking != 0 || style.Transform != NoTransform {
+		return 0, 0, false
+	}
+	for _, l := range standardLigatures {
+		if !strings.HasPrefix(s, l.text) {
+			continue
+		}
+		if index, err := this.glyphIndex(l.r); err == nil {
+			return index, len(l.text), true
+		}
+	}
+	return 0, 0, false
+}
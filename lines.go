@@ -0,0 +1,48 @@
+package gltext
+
+const (
+	LineSeparator      = '\u2028' //breaks the line without ending the paragraph
+	ParagraphSeparator = '\u2029'
+)
+
+func isLineBreak(r rune) bool {
+	return r == '\n' || r == '\r' || r == LineSeparator || r == ParagraphSeparator
+}
+
+//breakAt reports whether ch, at byte offset i of s, ends a line and whether it ends the paragraph too. the '\r' of
+//"\r\n" ends nothing, leaving that to the '\n', so Windows line endings break once.
+func breakAt(s string, i int, ch rune) (line, paragraph bool) {
+	switch ch {
+	case '\r':
+		if i+1 < len(s) && s[i+1] == '\n' {
+			return false, false
+		}
+		return true, true
+	case '\n', ParagraphSeparator:
+		return true, true
+	case LineSeparator:
+		return true, false
+	}
+	return false, false
+}
+
+//splitLines splits s at every line break that layout recognizes
+func splitLines(s string) []string {
+	lines := make([]string, 0)
+	start := 0
+	for i, ch := range s {
+		if line, _ := breakAt(s, i, ch); line {
+			lines = append(lines, trimCR(s[start:i]))
+			start = i + len(string(ch))
+		}
+	}
+	return append(lines, s[start:])
+}
+
+//trimCR removes the '\r' that is the first half of a "\r\n" ending line
+func trimCR(line string) string {
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		return line[:len(line)-1]
+	}
+	return line
+}
@@ -0,0 +1,120 @@
+package gltext
+
+//LinkStyle colors link text; a fully transparent color leaves the layout's own color in place
+type LinkStyle struct {
+	Color          Vector4
+	HoverColor     Vector4
+	UnderlineHover bool
+}
+
+func DefaultLinkStyle() LinkStyle {
+	return LinkStyle{
+		Color:          Vector4{0.4, 0.6, 1, 1},
+		HoverColor:     Vector4{0.6, 0.8, 1, 1},
+		UnderlineHover: true,
+	}
+}
+
+//Link is a clickable span of a layout
+type Link struct {
+	Tag        string
+	Start, End int
+	Rects      []Rect
+}
+
+type link struct {
+	tag        string
+	start, end int
+}
+
+//AddLink tags the runes in [start, end) as a link; set LinkStyle beforehand to change how it is colored
+func (this *Layout) AddLink(start, end int, tag string) {
+	if start > end {
+		start, end = end, start
+	}
+	this.links = append(this.links, link{tag, start, end})
+	this.colorLink(len(this.links)-1, this.LinkStyle.Color)
+}
+
+func (this *Layout) colorLink(i int, color Vector4) {
+	l := this.links[i]
+	for g := l.start; g < l.end && g < len(this.glyphs); g++ {
+		if color[3] > 0 {
+			c := color
+			this.glyphs[g].color = &c
+		} else {
+			this.glyphs[g].color = this.spanColor(g)
+		}
+	}
+}
+
+//Links reports every link with its screen rectangles
+func (this *Layout) Links() []Link {
+	links := make([]Link, len(this.links))
+	for i, l := range this.links {
+		links[i] = Link{l.tag, l.start, l.end, this.SelectionRects(l.start, l.end)}
+	}
+	return links
+}
+
+func (this *Layout) linkAt(x, y float32) int {
+	index, ok := this.HitTest(x, y)
+	if !ok {
+		return -1
+	}
+	for i, l := range this.links {
+		if index >= l.start && index < l.end {
+			return i
+		}
+	}
+	return -1
+}
+
+//LinkAt returns the tag of the link under x, y
+func (this *Layout) LinkAt(x, y float32) (tag string, ok bool) {
+	if i := this.linkAt(x, y); i >= 0 {
+		return this.links[i].tag, true
+	}
+	return "", false
+}
+
+//Click calls OnLink if x, y is over a link and reports whether it was
+func (this *Layout) Click(x, y float32) bool {
+	tag, ok := this.LinkAt(x, y)
+	if ok && this.OnLink != nil {
+		this.OnLink(tag)
+	}
+	return ok
+}
+
+//Hover applies the hover style to the link under x, y, if any; call it when the mouse moves
+func (this *Layout) Hover(x, y float32) {
+	i := this.linkAt(x, y)
+	if i == this.hovered {
+		return
+	}
+	if this.hovered >= 0 {
+		this.colorLink(this.hovered, this.LinkStyle.Color)
+	}
+	this.hovered = i
+	if i >= 0 {
+		this.colorLink(i, this.LinkStyle.HoverColor)
+	}
+}
+
+func (this *Layout) drawLinkDecorations(alpha float32) {
+	if this.hovered < 0 || !this.LinkStyle.UnderlineHover {
+		return
+	}
+	l := this.links[this.hovered]
+	color := this.LinkStyle.HoverColor
+	if color[3] == 0 {
+		color = this.style.Color
+	}
+	color[3] *= alpha
+	baseline := this.baseline()
+	thickness := baseline / 12
+	for _, r := range this.SelectionRects(l.start, l.end) {
+		this.font.rects.draw(r.X, r.Y-baseline-thickness, r.W, thickness, color)
+	}
+}
@@ -0,0 +1,132 @@
+package gltext
+
+import (
+	"fmt"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+	"golang.org/x/text/number"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+//Locale formats values the way a given language expects before they're laid out, using golang.org/x/text.
+//Fonts only cover ASCII by default, so bake Digits() into the atlas (see NewFontRanges) for locales such as
+//ar-EG whose digits fall outside it.
+type Locale struct {
+	tag     language.Tag
+	printer *message.Printer
+	digits  [10]rune
+}
+
+//NewLocale parses a BCP 47 tag such as "de-DE" or "ar-EG"
+func NewLocale(tag string) (*Locale, error) {
+	t, err := language.Parse(tag)
+	if err != nil {
+		return nil, err
+	}
+	return newLocale(t), nil
+}
+
+//NewLocaleCatalog is NewLocale with translations looked up in cat instead of message.DefaultCatalog
+func NewLocaleCatalog(tag string, cat catalog.Catalog) (*Locale, error) {
+	t, err := language.Parse(tag)
+	if err != nil {
+		return nil, err
+	}
+	return newLocale(t, message.Catalog(cat)), nil
+}
+
+//MatchLocale returns the locale of cat's languages that best suits the user's preferred ones, in order of preference,
+//such as "fr-CH" or an Accept-Language header. with none in common it returns cat's first language.
+func MatchLocale(cat catalog.Catalog, preferred ...string) *Locale {
+	t, _ := language.MatchStrings(language.NewMatcher(cat.Languages()), preferred...)
+	return newLocale(t, message.Catalog(cat))
+}
+
+func newLocale(t language.Tag, options ...message.Option) *Locale {
+	this := &Locale{tag: t, printer: message.NewPrinter(t, options...)}
+	for d := range this.digits {
+		this.digits[d] = '0' + rune(d)
+		if s := this.printer.Sprint(number.Decimal(d)); s != "" {
+			this.digits[d], _ = utf8.DecodeRuneInString(s)
+		}
+	}
+	return this
+}
+
+func (this *Locale) String() string {
+	return this.tag.String()
+}
+
+//Digits returns the locale's zero through nine
+func (this *Locale) Digits() RuneRange {
+	return RuneRange{this.digits[0], this.digits[9]}
+}
+
+//Sprintf is fmt.Sprintf with locale aware number formatting. fs is first looked up as a key in the locale's catalog,
+//so that a translation, with its plural forms and reordered arguments, is formatted in its place.
+func (this *Locale) Sprintf(fs string, argv ...interface{}) string {
+	return this.printer.Sprintf(fs, argv...)
+}
+
+//Number formats v with grouping separators and at most decimals fraction digits
+func (this *Locale) Number(v float64, decimals int) string {
+	return this.printer.Sprint(number.Decimal(v, number.MaxFractionDigits(decimals)))
+}
+
+//Percent formats a fraction, so 0.25 becomes "25%" in English
+func (this *Locale) Percent(v float64, decimals int) string {
+	return this.printer.Sprint(number.Percent(v, number.MaxFractionDigits(decimals)))
+}
+
+//Date formats t with a time package layout, substituting the locale's digits.
+//month and day names stay in English; x/text has no calendar data.
+func (this *Locale) Date(t time.Time, layout string) string {
+	return this.localizeDigits(t.Format(layout))
+}
+
+func (this *Locale) localizeDigits(s string) string {
+	if this.digits[0] == '0' {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return this.digits[r-'0']
+		}
+		return r
+	}, s)
+}
+
+//SetLocale has Printf, PrintfStyle, Layout and the Printf of queues and consoles look their format strings up in
+//loc's catalog and format their arguments as loc does, so translated UIs pass keys in place of text. nil goes back to
+//fmt.Sprintf.
+func (this *Font) SetLocale(loc *Locale) {
+	localeMu.Lock()
+	this.locale = loc
+	localeMu.Unlock()
+}
+
+func (this *Font) Locale() *Locale {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	return this.locale
+}
+
+//localeMu guards the locale of every font, which Queue.Printf reads from the goroutines submitting to it
+var localeMu sync.RWMutex
+
+//sprintf formats for drawing, through the font's locale if it has one
+func (this *Font) sprintf(fs string, argv ...interface{}) string {
+	if loc := this.Locale(); loc != nil {
+		return loc.Sprintf(fs, argv...)
+	}
+	return fmt.Sprintf(fs, argv...)
+}
+
+//PrintfLocale is like Printf but formats argv according to loc
+func (this *Font) PrintfLocale(x, y float32, loc *Locale, fs string, argv ...interface{}) {
+	this.print(x, y, loc.Sprintf(fs, argv...), this.style)
+}
@@ -0,0 +1,30 @@
+package gltext
+
+import (
+	"log"
+	"os"
+)
+
+//Logger receives gltext diagnostics; *log.Logger satisfies it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+var logger Logger = log.New(os.Stderr, "", log.LstdFlags)
+
+//SetLogger routes gltext diagnostics to l; a nil Logger silences them
+func SetLogger(l Logger) {
+	logger = l
+}
+
+func logf(format string, v ...interface{}) {
+	if logger != nil {
+		logger.Printf(format, v...)
+	}
+}
+
+//logFatal replaces log.Fatal: the message goes to the configured logger before exiting
+func logFatal(err error) {
+	logf("gltext: %v", err)
+	os.Exit(1)
+}
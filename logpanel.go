@@ -0,0 +1,207 @@
+package gltext
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//LogPanel is a log/slog.Handler that keeps the last lines logged through it and draws them in a corner of the screen,
+//colored by level, for builds with no console to log to. it may be logged to from any goroutine; Draw it on the GL thread.
+//the handlers WithAttrs and WithGroup return log into the same panel.
+type LogPanel struct {
+	Corner     Corner
+	Margin     float32 //distance from the screen edges, in draw units
+	Padding    float32 //space between the background edge and the text
+	Width      float32 //of the panel; lines are clipped to it
+	Background Vector4
+	Style      Style
+	DebugColor Vector4
+	InfoColor  Vector4
+	WarnColor  Vector4
+	ErrorColor Vector4
+	Level      slog.Leveler //the least level logged; nil for slog.LevelInfo
+	Time       bool         //start lines with the time of the record
+
+	font   *Font
+	log    *panelLog
+	attrs  string //formatted by WithAttrs
+	groups string //the qualifier of keys from WithGroup, ending in '.'
+}
+
+//panelLog is what a LogPanel and the handlers derived from it share
+type panelLog struct {
+	mu      sync.Mutex
+	entries []panelEntry
+	head    int //index of the oldest entry once entries is full
+	count   int
+}
+
+type panelEntry struct {
+	level slog.Level
+	text  string
+}
+
+//NewLogPanel shows the last lines logged, to a width of width draw units
+func NewLogPanel(font *Font, width float32, lines int) *LogPanel {
+	return &LogPanel{
+		Corner:     BottomLeft,
+		Margin:     0.02,
+		Padding:    0.01,
+		Width:      width,
+		Background: Vector4{0, 0, 0, 0.6},
+		Style:      font.style,
+		DebugColor: Vector4{0.6, 0.6, 0.6, 1},
+		InfoColor:  Vector4{1, 1, 1, 1},
+		WarnColor:  Vector4{1, 0.8, 0.2, 1},
+		ErrorColor: Vector4{1, 0.3, 0.3, 1},
+		font:       font,
+		log:        &panelLog{entries: make([]panelEntry, lines)},
+	}
+}
+
+func (this *LogPanel) Enabled(_ context.Context, level slog.Level) bool {
+	least := slog.LevelInfo
+	if this.Level != nil {
+		least = this.Level.Level()
+	}
+	return level >= least
+}
+
+//Handle adds the record as "LEVEL message key=value...", one entry for each line of it
+func (this *LogPanel) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if this.Time && !r.Time.IsZero() {
+		b.WriteString(r.Time.Format(time.TimeOnly))
+		b.WriteByte(' ')
+	}
+	b.WriteString(r.Level.String())
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+	b.WriteString(this.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&b, this.groups, a)
+		return true
+	})
+	this.log.add(r.Level, splitLines(b.String()))
+	return nil
+}
+
+func (this *LogPanel) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, a := range attrs {
+		writeAttr(&b, this.groups, a)
+	}
+	c := *this
+	c.attrs += b.String()
+	return &c
+}
+
+func (this *LogPanel) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return this
+	}
+	c := *this
+	c.groups += name + "."
+	return &c
+}
+
+//writeAttr writes " key=value", with the keys of groups qualified by their name
+func writeAttr(b *strings.Builder, groups string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	value := a.Value
+	if value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups += a.Key + "."
+		}
+		for _, g := range value.Group() {
+			writeAttr(b, groups, g)
+		}
+		return
+	}
+	s := value.String()
+	if s == "" || strings.ContainsAny(s, " =\"") {
+		s = strconv.Quote(s)
+	}
+	b.WriteByte(' ')
+	b.WriteString(groups + a.Key)
+	b.WriteByte('=')
+	b.WriteString(s)
+}
+
+func (this *panelLog) add(level slog.Level, lines []string) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	capacity := len(this.entries)
+	if capacity == 0 {
+		return
+	}
+	for _, line := range lines {
+		if this.count < capacity {
+			this.entries[(this.head+this.count)%capacity] = panelEntry{level, line}
+			this.count++
+		} else {
+			this.entries[this.head] = panelEntry{level, line}
+			this.head = (this.head + 1) % capacity
+		}
+	}
+}
+
+//snapshot returns the entries, oldest first
+func (this *panelLog) snapshot() []panelEntry {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	entries := make([]panelEntry, this.count)
+	for i := range entries {
+		entries[i] = this.entries[(this.head+i)%len(this.entries)]
+	}
+	return entries
+}
+
+func (this *LogPanel) Clear() {
+	this.log.mu.Lock()
+	defer this.log.mu.Unlock()
+	this.log.head, this.log.count = 0, 0
+}
+
+func (this *LogPanel) levelColor(level slog.Level) Vector4 {
+	switch {
+	case level < slog.LevelInfo:
+		return this.DebugColor
+	case level < slog.LevelWarn:
+		return this.InfoColor
+	case level < slog.LevelError:
+		return this.WarnColor
+	}
+	return this.ErrorColor
+}
+
+//Draw draws the panel, if anything has been logged, with as many lines as it keeps
+func (this *LogPanel) Draw() {
+	entries := this.log.snapshot()
+	if len(entries) == 0 {
+		return
+	}
+	font := this.font
+	boxWidth := this.Width
+	boxHeight := this.Padding*2 + font.cellHeight*float32(len(entries))
+
+	x, y := cornerOrigin(this.Corner, this.Margin, this.Margin, boxWidth, boxHeight)
+	if this.Background[3] > 0 {
+		font.fillRect(x, y, boxWidth, boxHeight, this.Background)
+	}
+	inner := Rect{x + this.Padding, y - this.Padding, boxWidth - 2*this.Padding, boxHeight - 2*this.Padding}
+	font.withClip(inner, func() {
+		for i, e := range entries {
+			style := this.Style
+			style.Color = this.levelColor(e.level)
+			font.print(inner.X, inner.Y-float32(i)*font.cellHeight, e.text, style)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package gltext
+
+import (
+	"strings"
+	"unicode"
+)
+
+//MarkdownStyle is how LayoutMarkdown draws each part of the text, each in its font's own style. fonts left nil fall
+//back to Regular, and headings to Bold.
+type MarkdownStyle struct {
+	Regular, Bold, Italic, BoldItalic *Font
+	Code                              *Font
+	Headings                          [3]*Font //for #, ## and ###
+	Bullet                            string   //drawn before each list item
+	ParagraphSpacing                  float32  //between paragraphs and headings, but not between list items
+	CodeBackground                    Vector4  //drawn behind inline code, unless fully transparent
+}
+
+func DefaultMarkdownStyle(regular *Font) MarkdownStyle {
+	return MarkdownStyle{
+		Regular:          regular,
+		Bullet:           "-",
+		ParagraphSpacing: regular.cellHeight / 2,
+		CodeBackground:   Vector4{1, 1, 1, 0.15},
+	}
+}
+
+//markdownEmphasis says how a span of text is set
+type markdownEmphasis int
+
+const (
+	markdownBold markdownEmphasis = 1 << iota
+	markdownItalic
+	markdownCode
+)
+
+type markdownSpan struct {
+	text     string
+	emphasis markdownEmphasis
+}
+
+//markdownBlock is a heading, list item or paragraph
+type markdownBlock struct {
+	heading int //1 to 3 for a heading, otherwise 0
+	bullet  bool
+	spans   []markdownSpan
+}
+
+//parseMarkdown splits s into blocks. lines that follow one another are joined into one paragraph or list item, and
+//blank lines end them.
+func parseMarkdown(s string) []markdownBlock {
+	var blocks []markdownBlock
+	var text []string
+	var block markdownBlock
+	end := func() {
+		if len(text) > 0 {
+			block.spans = parseMarkdownInline(strings.Join(text, " "))
+			blocks = append(blocks, block)
+		}
+		text, block = nil, markdownBlock{}
+	}
+	for _, line := range splitLines(s) {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			end()
+		case strings.HasPrefix(line, "#"):
+			level := len(line) - len(strings.TrimLeft(line, "#"))
+			if rest := line[level:]; rest == "" || rest[0] == ' ' {
+				end()
+				block.heading = minInt(level, 3)
+				text = append(text, strings.TrimSpace(rest))
+				end()
+				continue
+			}
+			text = append(text, line)
+		case len(line) >= 2 && strings.ContainsRune("-*+", rune(line[0])) && line[1] == ' ':
+			end()
+			block.bullet = true
+			text = append(text, strings.TrimSpace(line[2:]))
+		default:
+			text = append(text, line)
+		}
+	}
+	end()
+	return blocks
+}
+
+//parseMarkdownInline splits s into spans of **bold**, *italic* and `code`, with __ and _ for ** and *. a marker
+//that can't open or close emphasis is left as it is, as is _ inside a word, and a backslash escapes the character
+//after it.
+func parseMarkdownInline(s string) []markdownSpan {
+	var spans []markdownSpan
+	var text strings.Builder
+	emphasis := markdownEmphasis(0)
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, markdownSpan{text.String(), emphasis})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_#-+", s[i+1]) >= 0:
+			i++
+			text.WriteByte(s[i])
+		case c == '`':
+			end := strings.IndexByte(s[i+1:], '`')
+			if end < 0 {
+				text.WriteByte(c)
+				continue
+			}
+			flush()
+			spans = append(spans, markdownSpan{s[i+1 : i+1+end], emphasis | markdownCode})
+			i += end + 1
+		case c == '*' || c == '_':
+			marker, e := s[i:i+1], markdownItalic
+			if i+1 < len(s) && s[i+1] == c {
+				marker, e = s[i:i+2], markdownBold
+			}
+			opening := emphasis&e == 0
+			rest := s[i+len(marker):]
+			if c == '_' && inWord(s, i, len(marker)) || opening && (strings.HasPrefix(rest, " ") || !closes(rest, marker)) {
+				text.WriteString(marker)
+				i += len(marker) - 1
+				continue
+			}
+			flush()
+			emphasis ^= e
+			i += len(marker) - 1
+		default:
+			text.WriteByte(c)
+		}
+	}
+	flush()
+	return spans
+}
+
+//closes reports whether s has marker where it can end emphasis: not escaped, and not after a space
+func closes(s, marker string) bool {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], marker) && i > 0 && s[i-1] != ' ':
+			return true
+		}
+	}
+	return false
+}
+
+//inWord reports whether the marker of size bytes at i has letters or digits on both sides, as in snake_case
+func inWord(s string, i, size int) bool {
+	isAlnum := func(b byte) bool {
+		return b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+	}
+	return i > 0 && isAlnum(s[i-1]) && i+size < len(s) && isAlnum(s[i+size])
+}
+
+//font returns the font of a span of a block
+func (this MarkdownStyle) font(block markdownBlock, emphasis markdownEmphasis) *Font {
+	pick := func(fonts ...*Font) *Font {
+		for _, f := range fonts {
+			if f != nil {
+				return f
+			}
+		}
+		return this.Regular
+	}
+	switch {
+	case emphasis&markdownCode != 0:
+		return pick(this.Code)
+	case block.heading > 0:
+		return pick(this.Headings[block.heading-1], this.Bold)
+	case emphasis == markdownBold|markdownItalic:
+		return pick(this.BoldItalic, this.Bold, this.Italic)
+	case emphasis == markdownBold:
+		return pick(this.Bold)
+	case emphasis == markdownItalic:
+		return pick(this.Italic)
+	}
+	return this.Regular
+}
+
+//LayoutMarkdown lays out a small subset of Markdown from x, y: # headings to three levels, - lists, and **bold**,
+//*italic* and `code` within them. lines are wrapped at spaces to fit maxWidth, unless it is zero.
+func LayoutMarkdown(x, y, maxWidth float32, style MarkdownStyle, s string) *RunLines {
+	this := newRunLines(x, y)
+	bullet := false
+	for i, block := range parseMarkdown(s) {
+		if i > 0 && !(bullet && block.bullet) {
+			this.end -= style.ParagraphSpacing
+		}
+		bullet = block.bullet
+		runs, indent := style.runs(block)
+		this.wrap(maxWidth, indent, runs)
+	}
+	return this
+}
+
+//runs returns the runs of block, and how far lines after the first are indented
+func (this MarkdownStyle) runs(block markdownBlock) ([]Run, float32) {
+	var runs []Run
+	indent := float32(0)
+	if block.bullet {
+		marker := Run{Font: this.Regular, Text: this.Bullet + " "}
+		indent = marker.width(marker.Text)
+		runs = append(runs, marker)
+	}
+	for _, span := range block.spans {
+		font := this.font(block, span.emphasis)
+		run := Run{Font: font, Text: span.text}
+		if span.emphasis&markdownCode != 0 && this.CodeBackground[3] > 0 {
+			code := font.style
+			code.Background = Background{Color: this.CodeBackground}
+			run.Style = &code
+		}
+		runs = append(runs, run)
+	}
+	return runs, indent
+}
@@ -0,0 +1,97 @@
+package gltext
+
+import (
+	"time"
+)
+
+//Marquee scrolls a single line of text that is wider than its container, clipped to the container.
+//Looping marquees scroll continuously with a copy of the text following Gap behind; otherwise the text scrolls back and forth between its ends.
+//Text that fits is drawn still.
+type Marquee struct {
+	X, Y, Width float32
+	Speed       float32 //draw units per second
+	Pause       time.Duration
+	Loop        bool
+	Gap         float32
+	Style       Style
+
+	font      *Font
+	content   *Layout
+	offset    float32
+	direction float32
+	paused    time.Duration
+}
+
+func NewMarquee(font *Font, x, y, width float32) *Marquee {
+	this := &Marquee{
+		X:         x,
+		Y:         y,
+		Width:     width,
+		Speed:     0.25,
+		Pause:     time.Second,
+		Gap:       width / 3,
+		Style:     font.style,
+		font:      font,
+		direction: 1,
+	}
+	this.SetText("")
+	return this
+}
+
+func (this *Marquee) SetText(s string) {
+	this.content = this.font.LayoutStyle(this.X, this.Y, this.Style, s)
+	this.Reset()
+}
+
+//Reset returns the text to its starting position
+func (this *Marquee) Reset() {
+	this.offset = 0
+	this.direction = 1
+	this.paused = 0
+}
+
+func (this *Marquee) overflow() float32 {
+	return this.content.Width() - this.Width
+}
+
+//Update advances the animation by dt
+func (this *Marquee) Update(dt time.Duration) {
+	if this.overflow() <= 0 {
+		return
+	}
+	if this.paused < this.Pause {
+		this.paused += dt
+		if this.paused < this.Pause {
+			return
+		}
+		dt = this.paused - this.Pause
+	}
+	this.offset += this.direction * this.Speed * float32(dt.Seconds())
+
+	if this.Loop {
+		period := this.content.Width() + this.Gap
+		if this.offset >= period {
+			this.offset -= period
+			this.paused = 0
+		}
+		return
+	}
+	if this.offset >= this.overflow() {
+		this.offset = this.overflow()
+		this.direction = -1
+		this.paused = 0
+	} else if this.offset <= 0 {
+		this.offset = 0
+		this.direction = 1
+		this.paused = 0
+	}
+}
+
+func (this *Marquee) Draw() {
+	this.font.withClip(Rect{this.X, this.Y, this.Width, this.content.Height()}, func() {
+		this.content.MoveTo(this.X-this.offset, this.Y).Draw()
+		if this.Loop && this.overflow() > 0 {
+			this.content.MoveTo(this.X-this.offset+this.content.Width()+this.Gap, this.Y).Draw()
+		}
+	})
+}
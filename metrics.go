@@ -0,0 +1,64 @@
+package gltext
+
+//Ascent is the distance from the top of the font's line to its baseline, in draw units
+func (this *Font) Ascent() float32 {
+	return this.baseline
+}
+
+//Descent is the distance from the font's baseline to the bottom of its line, in draw units
+func (this *Font) Descent() float32 {
+	return this.cellHeight - this.baseline
+}
+
+//LineHeight is the distance between the tops of successive lines, in draw units
+func (this *Font) LineHeight() float32 {
+	return this.cellHeight
+}
+
+//CapHeight is the height of a flat capital above the baseline, measured from 'H', in draw units
+func (this *Font) CapHeight() float32 {
+	return this.inkHeight('H', 0.7)
+}
+
+//XHeight is the height of a flat lowercase letter above the baseline, measured from 'x', in draw units
+func (this *Font) XHeight() float32 {
+	return this.inkHeight('x', 0.5)
+}
+
+//inkHeight is how far ch's ink rises above the baseline, or guess times the ascent if the font lacks it
+func (this *Font) inkHeight(ch rune, guess float32) float32 {
+	index, err := this.glyphIndex(ch)
+	if err != nil || this.atlas.ink[index].H == 0 {
+		return this.baseline * guess
+	}
+	//ink is measured down from the top of the quad, so its top is above the baseline by the difference
+	return this.baseline + this.atlas.ink[index].Y
+}
+
+//Bounds returns the tight extents of the ink s would leave if printed at 0, 0 in the font's style, including
+//descenders and accents, unlike the advance based measure of Layout.Rect
+func (this *Font) Bounds(s string) Rect {
+	return this.LayoutStyle(0, 0, this.style, s).InkRect()
+}
+
+//InkRect is the tight bounds of the layout's glyphs and inline elements, or an empty rectangle at its origin if
+//nothing in it leaves a mark
+func (this *Layout) InkRect() Rect {
+	bounds := Rect{this.x, this.y, 0, 0}
+	baseline := this.font.baseline
+	for _, g := range this.glyphs {
+		if g.inline != nil {
+			bottom := g.y - this.baseline() - g.inline.Descent
+			bounds = bounds.Union(Rect{g.x, bottom + g.inline.Height, g.inline.Width, g.inline.Height})
+			continue
+		}
+		if g.index < 0 || this.font.atlas.ink[g.index].W == 0 {
+			continue
+		}
+		//glyphs are scaled about their baseline origin, which is baseline below the top of their quad
+		ink, scale := this.font.atlas.ink[g.index], g.drawScale()
+		x, y := g.x+g.inset, g.y-baseline
+		bounds = bounds.Union(Rect{x + ink.X*scale, y + (baseline+ink.Y)*scale, ink.W * scale, ink.H * scale})
+	}
+	return bounds
+}
@@ -0,0 +1,38 @@
+package gltext
+
+//MissingGlyph is a rune that laying out text found no glyph for, and where it was found
+type MissingGlyph struct {
+	Rune   rune
+	Text   string //the whole text being laid out
+	Offset int    //the byte offset of Rune in Text
+}
+
+//SetMissingGlyphHook has hook called whenever laying out text meets a rune the font can't draw, to log gaps in a
+//localization, say. hook returns a rune to draw in its place, or 0 to leave it out as without a hook. layouts are
+//cached, so text drawn again unchanged doesn't call hook again.
+func (this *Font) SetMissingGlyphHook(hook func(MissingGlyph) rune) {
+	this.missingGlyph = hook
+	this.layouts.clear()
+}
+
+//substituteGlyph shapes what the missing glyph hook returns for m, which is left out if the font lacks that as well
+func (this *Font) substituteGlyph(m MissingGlyph, style Style) shapedGlyph {
+	if r := this.missingGlyph(m); r != 0 {
+		if g, ok := this.shapeRune(r, style); ok {
+			return g
+		}
+	}
+	return shapedGlyph{index: -1, missing: true}
+}
+
+//shapeRune shapes r on its own, to be drawn in place of another rune
+func (this *Font) shapeRune(r rune, style Style) (shapedGlyph, bool) {
+	shown, scale := style.Transform.apply(r)
+	index, err := this.glyphIndex(shown)
+	if err != nil {
+		return shapedGlyph{}, false
+	}
+	g := shapedGlyph{index: index, scale: scale}
+	g.advance = this.atlas.offsets[index]*layoutGlyph{scale: scale}.drawScale() + style.Tracking
+	return g, true
+}
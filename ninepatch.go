@@ -0,0 +1,62 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"image"
+)
+
+//NinePatch is a texture for resizable panels: the corners are drawn unscaled, the edges stretch along one axis and the centre stretches along both.
+//the insets give the size of the border in texture pixels.
+type NinePatch struct {
+	Left, Top, Right, Bottom int
+
+	texture       gl.Texture
+	width, height int
+}
+
+//NewNinePatch uploads img as a texture; it must be called on the GL thread
+func NewNinePatch(img image.Image, left, top, right, bottom int) *NinePatch {
+	tex, width, height := uploadImage(img)
+	return &NinePatch{
+		Left:    left,
+		Top:     top,
+		Right:   right,
+		Bottom:  bottom,
+		texture: tex,
+		width:   width,
+		height:  height,
+	}
+}
+
+func (this *NinePatch) Delete() {
+	this.texture.Delete()
+}
+
+//drawNinePatch stretches the patch over r, tinted by color
+func (this *Font) drawNinePatch(patch *NinePatch, r Rect, color Vector4) {
+	restore := this.applyClip()
+	defer restore()
+
+	//border sizes in draw units and in texture coordinates
+	px, py := 2/this.source.width, 2/this.source.height
+	xs := [4]float32{r.X, r.X + float32(patch.Left)*px, r.X + r.W - float32(patch.Right)*px, r.X + r.W}
+	ys := [4]float32{r.Y, r.Y - float32(patch.Top)*py, r.Y - r.H + float32(patch.Bottom)*py, r.Y - r.H}
+	tw, th := float32(patch.width), float32(patch.height)
+	us := [4]float32{0, float32(patch.Left) / tw, 1 - float32(patch.Right)/tw, 1}
+	vs := [4]float32{0, float32(patch.Top) / th, 1 - float32(patch.Bottom)/th, 1}
+
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			w, h := xs[col+1]-xs[col], ys[row]-ys[row+1]
+			if w <= 0 || h <= 0 {
+				continue
+			}
+			this.rects.drawQuad(quad{
+				rect:    Rect{xs[col], ys[row], w, h},
+				color:   color,
+				texture: patch.texture,
+				uv:      Vector4{us[col], vs[row], us[col+1], vs[row+1]},
+			})
+		}
+	}
+}
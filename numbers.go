@@ -0,0 +1,69 @@
+package gltext
+
+import (
+	"strconv"
+)
+
+//PrintInt draws v without going through fmt or building a Layout, so it doesn't allocate once warmed up.
+//it honours the color, shadow, outline, tracking and tabular figures of the font's style; other style options are ignored.
+func (this *Font) PrintInt(x, y float32, v int64) {
+	this.numberBuf = strconv.AppendInt(this.numberBuf[:0], v, 10)
+	this.printASCII(x, y, this.numberBuf)
+}
+
+//PrintFloat is the allocation free counterpart of Printf("%.*f", precision, v)
+func (this *Font) PrintFloat(x, y float32, v float64, precision int) {
+	this.numberBuf = strconv.AppendFloat(this.numberBuf[:0], v, 'f', precision, 64)
+	this.printASCII(x, y, this.numberBuf)
+}
+
+func (this *Font) printASCII(x, y float32, b []byte) {
+	style := this.themed(this.style, 1)
+	this.batchASCII(x, y, b)
+	restore := this.applyClip()
+	if style.Shadow.Color[3] > 0 {
+		this.drawASCII(style.Shadow.Offset[0], style.Shadow.Offset[1], style.Shadow.Color)
+	}
+	if style.Outline.Width > 0 {
+		for _, d := range outlineDirections {
+			this.drawASCII(d[0]*style.Outline.Width, d[1]*style.Outline.Width, style.Outline.Color)
+		}
+	}
+	this.drawASCII(0, 0, style.Color)
+	this.flushGlyphs()
+	restore()
+}
+
+//batchASCII lays b out along one line from x, y into the font's batch
+func (this *Font) batchASCII(x, y float32, b []byte) {
+	tabular := float32(0)
+	if this.style.TabularFigures {
+		tabular = this.digitWidth()
+	}
+	this.beginBatch()
+	this.stats.Strings++
+	penX := x
+	for i, c := range b {
+		index, err := this.glyphIndex(rune(c))
+		if err != nil {
+			continue
+		}
+		advance := this.atlas.offsets[index]
+		inset := float32(0)
+		if tabular > 0 && c >= '0' && c <= '9' {
+			inset = (tabular - advance) / 2
+			advance = tabular
+		}
+		this.batchGlyph(i, index, penX+inset, y, 1, 0, nil, 0)
+		penX += advance + this.style.Tracking
+	}
+	if this.inventoryOn {
+		this.recordText(string(b), Rect{x, y, penX - x, this.cellHeight})
+	}
+	this.uploadBatch()
+}
+
+//drawASCII queues a pass over the batch made by batchASCII, moved by dx, dy
+func (this *Font) drawASCII(dx, dy float32, color Vector4) {
+	this.queueGlyphs(this.runs, color, dx, dy, 0, Animation{}, false, false, 1)
+}
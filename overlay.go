@@ -0,0 +1,152 @@
+package gltext
+
+import (
+	"fmt"
+)
+
+type Corner int
+
+const (
+	TopLeft Corner = iota
+	TopRight
+	BottomLeft
+	BottomRight
+)
+
+//cornerOrigin returns the top left of a w by h box kept marginX from the side and marginY from the top or bottom of
+//the screen at corner. draw space spans 2 units across and 2 units down from the top left of the screen.
+func cornerOrigin(corner Corner, marginX, marginY, w, h float32) (x, y float32) {
+	x, y = marginX, -marginY
+	if corner == TopRight || corner == BottomRight {
+		x = 2 - marginX - w
+	}
+	if corner == BottomLeft || corner == BottomRight {
+		y = -2 + marginY + h
+	}
+	return x, y
+}
+
+//Overlay draws labelled rows such as frame time and draw calls in a corner of the screen, with labels and values in aligned columns
+type Overlay struct {
+	Corner     Corner
+	Margin     float32 //distance from the screen edges, in draw units
+	Padding    float32 //space between the background edge and the text
+	Background Vector4
+	LabelStyle Style
+	ValueStyle Style
+	GaugeColor Vector4
+
+	font *Font
+	rows []overlayRow
+}
+
+type overlayRow struct {
+	label string
+	value string
+	gauge float32 //fraction filled, or negative for a text row
+}
+
+func NewOverlay(font *Font) *Overlay {
+	return &Overlay{
+		Corner:     TopLeft,
+		Margin:     0.02,
+		Padding:    0.01,
+		Background: Vector4{0, 0, 0, 0.6},
+		LabelStyle: Style{Color: Vector4{0.7, 0.7, 0.7, 1}},
+		ValueStyle: font.style,
+		GaugeColor: Vector4{0.3, 0.8, 0.3, 1},
+		font:       font,
+	}
+}
+
+//Set updates the row with the given label, adding it at the bottom if it is new
+func (this *Overlay) Set(label string, fs string, argv ...interface{}) {
+	this.set(overlayRow{label, fmt.Sprintf(fs, argv...), -1})
+}
+
+//Gauge shows value/max as a bar next to the label
+func (this *Overlay) Gauge(label string, value, max float32) {
+	fraction := float32(0)
+	if max > 0 {
+		fraction = value / max
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	if fraction < 0 {
+		fraction = 0
+	}
+	this.set(overlayRow{label, "", fraction})
+}
+
+func (this *Overlay) set(row overlayRow) {
+	for i := range this.rows {
+		if this.rows[i].label == row.label {
+			this.rows[i] = row
+			return
+		}
+	}
+	this.rows = append(this.rows, row)
+}
+
+func (this *Overlay) Remove(label string) {
+	for i := range this.rows {
+		if this.rows[i].label == label {
+			this.rows = append(this.rows[:i], this.rows[i+1:]...)
+			return
+		}
+	}
+}
+
+func (this *Overlay) Clear() {
+	this.rows = this.rows[:0]
+}
+
+func (this *Overlay) Draw() {
+	if len(this.rows) == 0 {
+		return
+	}
+	font := this.font
+	labels := make([]*Layout, len(this.rows))
+	values := make([]*Layout, len(this.rows))
+	labelWidth, valueWidth := float32(0), float32(0)
+	for i, row := range this.rows {
+		labels[i] = font.LayoutStyle(0, 0, this.LabelStyle, row.label)
+		values[i] = font.LayoutStyle(0, 0, this.ValueStyle, row.value)
+		if w := labels[i].Width(); w > labelWidth {
+			labelWidth = w
+		}
+		if w := values[i].Width(); w > valueWidth {
+			valueWidth = w
+		}
+	}
+	gaugeWidth := font.cellHeight * 4
+	if valueWidth < gaugeWidth {
+		valueWidth = gaugeWidth
+	}
+	gap := font.cellHeight / 2
+	rowHeight := font.cellHeight
+
+	boxWidth := this.Padding*2 + labelWidth + gap + valueWidth
+	boxHeight := this.Padding*2 + rowHeight*float32(len(this.rows))
+
+	x, y := cornerOrigin(this.Corner, this.Margin, this.Margin, boxWidth, boxHeight)
+
+	if this.Background[3] > 0 {
+		font.fillRect(x, y, boxWidth, boxHeight, this.Background)
+	}
+
+	rowY := y - this.Padding
+	valueX := x + this.Padding + labelWidth + gap
+	for i, row := range this.rows {
+		labels[i].MoveTo(x+this.Padding, rowY).Draw()
+		if row.gauge >= 0 {
+			inset := rowHeight / 4
+			font.fillRect(valueX, rowY-inset, gaugeWidth*row.gauge, rowHeight-2*inset, this.GaugeColor)
+		} else {
+			//values are right aligned so that changing digits line up
+			values[i].MoveTo(valueX+valueWidth-values[i].Width(), rowY).Draw()
+		}
+		rowY -= rowHeight
+	}
+}
@@ -0,0 +1,104 @@
+package gltext
+
+import (
+	"math"
+	"sort"
+)
+
+//Path is a polyline in draw units, the same space Printf positions text in
+type Path [][2]float32
+
+//LayoutPath lays s out along path with each glyph turned to follow the segment under its centre, beginning
+//offset horizontal draw units from the first point. glyphs that run off either end carry on in a straight line.
+//lines after the first sit below the path as they would below a baseline. backgrounds and decorations aren't drawn,
+//and carets and hit testing still see the text as laid out in a straight line.
+func (this *Font) LayoutPath(path Path, offset float32, style Style, s string) *Layout {
+	style.Background = Background{}
+	style.Decorations = 0
+	layout := this.layoutText(0, 0, 0, style, s, nil)
+	layout.follow(newPathWalker(path, this.aspect()), offset)
+	return layout
+}
+
+//PathLength is the length of path in horizontal draw units, for comparing with Layout.Width when centring a label
+func (this *Font) PathLength(path Path) float32 {
+	return newPathWalker(path, this.aspect()).length() / this.aspect()
+}
+
+//aspect is the width of the screen over its height, which turns horizontal draw units into vertical ones
+func (this *Font) aspect() float32 {
+	return this.atlas.sy / this.atlas.sx
+}
+
+//curve is anything text can be laid along, measured in vertical draw units so that both axes agree
+type curve interface {
+	//at returns the point d along the curve, with x stretched by the screen's aspect, and the curve's angle there
+	at(d float32) (x, y, angle float32)
+}
+
+//follow moves each glyph so that its centre is on the curve, offset along it by the glyph's x
+func (this *Layout) follow(path curve, offset float32) {
+	aspect := this.font.aspect()
+	for i := range this.glyphs {
+		g := &this.glyphs[i]
+		half := g.advance / 2
+		x, y, angle := path.at((offset + g.x + half) * aspect)
+		sin, cos := math.Sincos(float64(angle))
+		//step back from the centre to where the glyph is drawn from, and out along the normal for later lines
+		ox, oy := (g.inset-half)*aspect, g.y
+		x += ox*float32(cos) - oy*float32(sin)
+		y += ox*float32(sin) + oy*float32(cos)
+		g.x, g.y, g.inset, g.angle = x/aspect, y, 0, angle
+	}
+}
+
+//pathWalker finds points by distance along a path
+type pathWalker struct {
+	points    [][2]float32
+	distances []float32 //from the first point to each point
+}
+
+func newPathWalker(path Path, aspect float32) *pathWalker {
+	this := &pathWalker{}
+	for _, p := range path {
+		p[0] *= aspect
+		if n := len(this.points); n > 0 {
+			last := this.points[n-1]
+			d := float32(math.Hypot(float64(p[0]-last[0]), float64(p[1]-last[1])))
+			if d == 0 {
+				continue
+			}
+			this.distances = append(this.distances, this.distances[n-1]+d)
+		} else {
+			this.distances = append(this.distances, 0)
+		}
+		this.points = append(this.points, p)
+	}
+	return this
+}
+
+func (this *pathWalker) length() float32 {
+	if len(this.distances) == 0 {
+		return 0
+	}
+	return this.distances[len(this.distances)-1]
+}
+
+func (this *pathWalker) at(d float32) (x, y, angle float32) {
+	switch len(this.points) {
+	case 0:
+		return d, 0, 0
+	case 1:
+		return this.points[0][0] + d, this.points[0][1], 0
+	}
+	i := sort.Search(len(this.distances), func(i int) bool { return this.distances[i] > d }) - 1
+	if i < 0 {
+		i = 0
+	} else if i > len(this.points)-2 {
+		i = len(this.points) - 2
+	}
+	a, b := this.points[i], this.points[i+1]
+	t := (d - this.distances[i]) / (this.distances[i+1] - this.distances[i])
+	angle = float32(math.Atan2(float64(b[1]-a[1]), float64(b[0]-a[0])))
+	return a[0] + (b[0]-a[0])*t, a[1] + (b[1]-a[1])*t, angle
+}
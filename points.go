@@ -0,0 +1,103 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"reflect"
+)
+
+//glyphPoint is a glyph of a batch on the point path: two opposite corners of its atlas quad, and how it is placed
+type glyphPoint struct {
+	x0, y0, x1, y1 int16
+	u0, v0, u1, v1 uint16
+	x, y           float32
+	scale, angle   float32
+	glyph, atlas   float32
+	colorIndex     float32
+}
+
+//glyphPoints holds the vertex array and buffer of the point path, whose attributes share the locations of glyphAttribs
+type glyphPoints struct {
+	vao    gl.VertexArray
+	vbo    gl.Buffer
+	points []glyphPoint
+	dsa    bool
+}
+
+func newGlyphPoints(dsa bool) *glyphPoints {
+	vao := gl.GenVertexArray()
+	vao.Bind()
+	vbo := gl.GenBuffer()
+	vbo.Bind(gl.ARRAY_BUFFER)
+	t := reflect.TypeOf(glyphPoint{})
+	stride := int(t.Size())
+	position, texcoord, placement, index := gl.AttribLocation(0), gl.AttribLocation(1), gl.AttribLocation(2), gl.AttribLocation(3)
+	position.AttribPointer(4, gl.SHORT, true, stride, nil)
+	position.EnableArray()
+	texcoord.AttribPointer(4, gl.UNSIGNED_SHORT, true, stride, uintptr(t.Field(4).Offset))
+	texcoord.EnableArray()
+	placement.AttribPointer(4, gl.FLOAT, false, stride, uintptr(t.Field(8).Offset))
+	placement.EnableArray()
+	index.AttribPointer(3, gl.FLOAT, false, stride, uintptr(t.Field(12).Offset))
+	index.EnableArray()
+	vbo.Unbind(gl.ARRAY_BUFFER)
+	vao.Unbind()
+	return &glyphPoints{vao: vao, vbo: vbo, dsa: dsa}
+}
+
+//add batches a glyph whose atlas quad runs from corner tl to corner br
+func (this *glyphPoints) add(tl, br glyphVertex, i, index, colorIndex int, x, y, scale, angle float32) {
+	this.points = append(this.points, glyphPoint{tl.x, tl.y, br.x, br.y, tl.u, tl.v, br.u, br.v, x, y, scale, angle, float32(i), float32(index), float32(colorIndex)})
+}
+
+func (this *glyphPoints) upload() {
+	bufferData(this.dsa, this.vbo, gl.ARRAY_BUFFER, int(reflect.TypeOf(glyphPoint{}).Size())*len(this.points), &this.points[0], gl.STREAM_DRAW)
+}
+
+func (this *glyphPoints) Delete() {
+	this.vao.Delete()
+	this.vbo.Delete()
+}
+
+//pointVertexShader hands each point's attributes on to the geometry shader
+const pointVertexShader = `
+    in vec4 position; //the top left and bottom right corners of the glyph's atlas quad
+    in vec4 texcoord;
+    in vec4 placement;
+    in vec3 glyphIndex;
+    out vec4 pointPosition;
+    out vec4 pointTexcoord;
+    out vec4 pointPlacement;
+    out vec3 pointIndex;
+    void main() {
+        pointPosition = position;
+        pointTexcoord = texcoord;
+        pointPlacement = placement;
+        pointIndex = glyphIndex;
+    }`
+
+const pointGeometryInputs = `
+    layout(points) in;
+    layout(triangle_strip, max_vertices = 4) out;
+    in vec4 pointPosition[];
+    in vec4 pointTexcoord[];
+    in vec4 pointPlacement[];
+    in vec3 pointIndex[];
+    vec2 position;
+    vec2 texcoord;
+    vec4 placement;
+    vec3 glyphIndex;`
+
+//pointGeometryMain emits the corners in the order of the quad indices, which makes a strip of two triangles
+const pointGeometryMain = `
+    void main() {
+        placement = pointPlacement[0];
+        glyphIndex = pointIndex[0];
+        for (int corner = 0; corner < 4; corner++) {
+            vec2 side = vec2(corner % 2, corner / 2);
+            position = mix(pointPosition[0].xy, pointPosition[0].zw, side);
+            texcoord = mix(pointTexcoord[0].xy, pointTexcoord[0].zw, side);
+            placeCorner(corner);
+            EmitVertex();
+        }
+        EndPrimitive();
+    }`
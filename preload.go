@@ -0,0 +1,81 @@
+package gltext
+
+import (
+	"code.google.com/p/freetype-go/freetype/truetype"
+)
+
+//Preload rasterizes and uploads every glyph s needs that the atlas lacks, all at once, so that drawing s later doesn't
+//stall loading them one by one, say in the middle of gameplay. the glyphs are loaded as dynamic glyphs, so it needs
+//SetDynamicGlyphs and must be called on the GL thread. it returns a *GlyphMissingError for the first rune it couldn't load.
+func (this *Font) Preload(s string) error {
+	return this.preload([]rune(s))
+}
+
+//PreloadRanges is Preload for every rune in the ranges. runes the font doesn't have are skipped without error.
+func (this *Font) PreloadRanges(ranges ...RuneRange) {
+	this.preload(expandRanges(ranges))
+}
+
+func (this *Font) preload(runes []rune) error {
+	a := this.atlas
+	var missing error
+	seen := make(map[rune]bool)
+	load := make([]rune, 0)
+	for _, ch := range runes {
+		if _, ok := a.runes[ch]; ok || seen[ch] {
+			continue
+		}
+		seen[ch] = true
+		if this.dynamic <= 0 || a.font == nil || a.font.Index(ch) == 0 {
+			if missing == nil {
+				missing = &GlyphMissingError{Rune: ch}
+			}
+			continue
+		}
+		load = append(load, ch)
+	}
+	//any more would evict glyphs preloaded a moment before
+	if len(load) > this.dynamic {
+		if missing == nil {
+			missing = &GlyphMissingError{Rune: load[this.dynamic]}
+		}
+		load = load[:this.dynamic]
+	}
+	if len(load) == 0 {
+		return missing
+	}
+
+	cells := rasterizeGlyphs(a.font, load, a.source.scale, a.source.dpi, a.cellWidth, a.cellPixels, a.baselinePixels, nil)
+	buf := truetype.NewGlyphBuf()
+	loaded := make([]int, 0, len(load))
+	grew, evicted := false, false
+	for k, ch := range load {
+		advance, ink := a.metrics(buf, ch)
+		if a.dynamicCount() >= this.dynamic {
+			if i := a.leastRecentlyUsed(); i >= 0 {
+				a.replace(i, ch, cells[k], advance, ink)
+				a.touch(i)
+				loaded, evicted = append(loaded, i), true
+				continue
+			}
+		}
+		grew = a.add(ch, cells[k], advance, ink) || grew
+		i := a.runes[ch]
+		a.evictable[i] = true
+		//count the glyph as used, so that the rest of the preload doesn't evict it
+		a.touch(i)
+		loaded = append(loaded, i)
+	}
+	if grew {
+		this.uploadAtlas()
+	} else {
+		for _, i := range loaded {
+			this.uploadCell(i)
+		}
+		this.packQuads()
+	}
+	if evicted {
+		this.atlasChanged()
+	}
+	return missing
+}
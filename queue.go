@@ -0,0 +1,117 @@
+package gltext
+
+import (
+	"sort"
+	"sync"
+)
+
+//Queue collects text draw commands from any goroutine; Flush must be called on the GL thread.
+//Flush groups the commands by font, keeping their order within each font, and draws each group as one batch.
+//text in different fonts that overlaps may then be drawn in a different order than it was queued; set KeepOrder to
+//draw every command in turn instead.
+type Queue struct {
+	KeepOrder bool
+
+	mu       sync.Mutex
+	commands []drawCommand
+	pending  []drawCommand
+	last     QueueStats
+}
+
+//QueueStats describes the GL work of a flush
+type QueueStats struct {
+	Commands     int
+	Batches      int //runs of commands drawn together
+	ProgramBinds int
+	TextureBinds int
+	DrawCalls    int
+}
+
+type drawCommand struct {
+	font  *Font
+	x, y  float32
+	s     string
+	style *Style //nil means the font's own style at the time of the flush
+}
+
+func NewQueue() *Queue {
+	return &Queue{}
+}
+
+//the string is formatted at submission time so argv may be safely modified after the call returns
+func (this *Queue) Printf(font *Font, x, y float32, fs string, argv ...interface{}) {
+	this.push(drawCommand{font, x, y, font.sprintf(fs, argv...), nil})
+}
+
+func (this *Queue) PrintfStyle(font *Font, x, y float32, style Style, fs string, argv ...interface{}) {
+	this.push(drawCommand{font, x, y, font.sprintf(fs, argv...), &style})
+}
+
+func (this *Queue) push(c drawCommand) {
+	this.mu.Lock()
+	this.commands = append(this.commands, c)
+	this.mu.Unlock()
+}
+
+func (this *Queue) Flush() {
+	//swap buffers so submitters are not blocked while we draw
+	this.mu.Lock()
+	this.commands, this.pending = this.pending[:0], this.commands
+	this.mu.Unlock()
+
+	commands := this.pending
+	if !this.KeepOrder {
+		first := make(map[*Font]int)
+		for i, c := range commands {
+			if _, ok := first[c.font]; !ok {
+				first[c.font] = i
+			}
+		}
+		sort.SliceStable(commands, func(i, j int) bool {
+			return first[commands[i].font] < first[commands[j].font]
+		})
+	}
+
+	stats := QueueStats{Commands: len(commands)}
+	for start := 0; start < len(commands); {
+		font := commands[start].font
+		end := start + 1
+		for end < len(commands) && commands[end].font == font && !this.KeepOrder {
+			end++
+		}
+		before := font.calls
+		font.drawCommands(commands[start:end])
+		calls := font.calls.sub(before)
+		stats.Batches++
+		stats.ProgramBinds += calls.programs
+		stats.TextureBinds += calls.textures
+		stats.DrawCalls += calls.draws
+		start = end
+	}
+	this.last = stats
+}
+
+//LastFlush reports the work done by the most recent Flush
+func (this *Queue) LastFlush() QueueStats {
+	return this.last
+}
+
+//drawCommands draws commands in the font together
+func (this *Font) drawCommands(commands []drawCommand) {
+	layouts, styles, alphas := make([]*Layout, len(commands)), make([]Style, len(commands)), make([]float32, len(commands))
+	used := make(map[*Layout]bool)
+	for i, c := range commands {
+		style := this.style
+		if c.style != nil {
+			style = *c.style
+		}
+		layout := this.cachedLayout(c.x, c.y, 0, style, c.s)
+		if used[layout] {
+			//the cache moves its layout to each place the same string is drawn, so later copies need their own
+			layout = this.layoutText(c.x, c.y, 0, style, c.s, nil)
+		}
+		used[layout] = true
+		layouts[i], styles[i], alphas[i] = layout, style, 1
+	}
+	this.drawLayouts(layouts, styles, alphas)
+}
@@ -0,0 +1,56 @@
+package gltext
+
+import (
+	"code.google.com/p/freetype-go/freetype"
+	"code.google.com/p/freetype-go/freetype/truetype"
+	"image"
+	"runtime"
+)
+
+//rasterizeGlyphs draws each rune into its own cellWidth x cellHeight bitmap.
+//a freetype.Context is not safe for concurrent use, so every worker gets its own; the parsed font is read-only and can be shared.
+//progress, if not nil, is called on the calling goroutine after each glyph completes.
+func rasterizeGlyphs(font *truetype.Font, runes []rune, scale int32, dpi float64, cellWidth, cellHeight, baseline int, progress func(done, total int)) []*image.RGBA {
+	cells := make([]*image.RGBA, len(runes))
+	jobs := make(chan int)
+	finished := make(chan int)
+
+	workers := runtime.NumCPU()
+	if workers > len(runes) {
+		workers = len(runes)
+	}
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			c := freetype.NewContext()
+			c.SetSrc(image.White)
+			c.SetDPI(dpi)
+			c.SetFontSize(float64(scale))
+			c.SetFont(font)
+
+			for i := range jobs {
+				cell := image.NewRGBA(image.Rect(0, 0, cellWidth, cellHeight))
+				c.SetDst(cell)
+				c.SetClip(cell.Bounds())
+				c.DrawString(string(runes[i]), freetype.Pt(0, baseline))
+				cells[i] = cell
+				finished <- i
+			}
+		}()
+	}
+
+	go func() {
+		for i := range runes {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	for done := 1; done <= len(runes); done++ {
+		<-finished
+		if progress != nil {
+			progress(done, len(runes))
+		}
+	}
+	return cells
+}
@@ -0,0 +1,176 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+)
+
+//rectRenderer draws rectangles in the same coordinate space as Printf, for decorations and backgrounds.
+//rectangles may be solid, have rounded corners or be textured.
+type rectRenderer struct {
+	program         gl.Program
+	vao             *vertexArray
+	vbo             gl.Buffer
+	rectUniform     gl.UniformLocation
+	colorUniform    gl.UniformLocation
+	uvUniform       gl.UniformLocation
+	pixelsUniform   gl.UniformLocation
+	radiusUniform   gl.UniformLocation
+	texturedUniform gl.UniformLocation
+	textureUniform  gl.UniformLocation
+	calls           *drawCalls //the counts of the font the renderer belongs to
+}
+
+type quad struct {
+	rect    Rect
+	color   Vector4
+	radius  float32    //corner radius in pixels
+	pixels  [2]float32 //size of rect in pixels, needed to round corners
+	texture gl.Texture //zero for a solid fill
+	uv      Vector4    //texture coordinates of the top left and bottom right corners
+
+	premultiplied bool //the texture's color has already been multiplied by its alpha
+}
+
+//blend is the usual alpha blend, except that destination alpha accumulates coverage,
+//so that drawing into a transparent texture leaves premultiplied color with the right opacity
+func blend() {
+	gl.Enable(gl.BLEND)
+	gl.BlendFuncSeparate(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+}
+
+func newRectRenderer() *rectRenderer {
+	vs, err := NewShader(gl.VERTEX_SHADER, `#version 150
+    in vec2 position;
+    out vec2 local;
+    out vec2 uv;
+    uniform vec4 rect;
+    uniform vec4 uvRect;
+    uniform vec2 pixels;
+    void main() {
+        gl_Position = vec4(-1 + rect.x + position.x * rect.z, 1 + rect.y - position.y * rect.w, 0, 1);
+        local = position * pixels;
+        uv = mix(uvRect.xy, uvRect.zw, position);
+    }`)
+	if err != nil {
+		logf("gltext: Error in rect vertex shader\n%v", err)
+	}
+
+	fs, err := NewShader(gl.FRAGMENT_SHADER, `#version 150
+    in vec2 local;
+    in vec2 uv;
+    uniform vec4 color;
+    uniform vec2 pixels;
+    uniform float radius;
+    uniform int textured;
+    uniform sampler2D tex;
+    out vec4 fragColor;
+    void main(void) {
+        vec4 c = color;
+        if (textured != 0) {
+            c *= texture(tex, uv);
+        }
+        if (radius > 0) {
+            vec2 q = abs(local - pixels * 0.5) - (pixels * 0.5 - radius);
+            float d = length(max(q, 0.0)) + min(max(q.x, q.y), 0.0) - radius;
+            c.a *= clamp(0.5 - d, 0.0, 1.0);
+        }
+        fragColor = c;
+    }`)
+	if err != nil {
+		logf("gltext: Error in rect fragment shader\n%v", err)
+	}
+
+	program := NewProgram(vs, fs)
+
+	//a unit quad, scaled and positioned by the rect uniform
+	vbo := gl.GenBuffer()
+	vbo.Bind(gl.ARRAY_BUFFER)
+	quad := []float32{0, 0, 1, 0, 0, 1, 1, 1}
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(quad), quad, gl.STATIC_DRAW)
+	vbo.Unbind(gl.ARRAY_BUFFER)
+
+	positionAttrib := program.GetAttribLocation("position")
+	vao := newVertexArray(func() []gl.AttribLocation {
+		vbo.Bind(gl.ARRAY_BUFFER)
+		positionAttrib.AttribPointer(2, gl.FLOAT, false, 0, nil)
+		positionAttrib.EnableArray()
+		vbo.Unbind(gl.ARRAY_BUFFER)
+		return []gl.AttribLocation{positionAttrib}
+	})
+
+	return &rectRenderer{
+		program:         program,
+		vao:             vao,
+		vbo:             vbo,
+		rectUniform:     program.GetUniformLocation("rect"),
+		colorUniform:    program.GetUniformLocation("color"),
+		uvUniform:       program.GetUniformLocation("uvRect"),
+		pixelsUniform:   program.GetUniformLocation("pixels"),
+		radiusUniform:   program.GetUniformLocation("radius"),
+		texturedUniform: program.GetUniformLocation("textured"),
+		textureUniform:  program.GetUniformLocation("tex"),
+		calls:           &drawCalls{},
+	}
+}
+
+//draw fills the rectangle whose top left corner is at x, y and which extends w right and h down
+func (this *rectRenderer) draw(x, y, w, h float32, color Vector4) {
+	this.drawQuad(quad{rect: Rect{x, y, w, h}, color: color})
+}
+
+func (this *rectRenderer) drawQuad(q quad) {
+	if q.premultiplied {
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	} else {
+		blend()
+	}
+
+	this.program.Use()
+	this.calls.programs++
+	this.calls.draws++
+	this.vao.Bind()
+	this.rectUniform.Uniform4f(q.rect.X, q.rect.Y, q.rect.W, q.rect.H)
+	this.colorUniform.Uniform4fv(1, q.color[:])
+	this.pixelsUniform.Uniform2f(q.pixels[0], q.pixels[1])
+	this.radiusUniform.Uniform1f(q.radius)
+	if q.texture != 0 {
+		gl.ActiveTexture(gl.TEXTURE0)
+		q.texture.Bind(gl.TEXTURE_2D)
+		this.calls.textures++
+		this.textureUniform.Uniform1i(0)
+		this.texturedUniform.Uniform1i(1)
+		this.uvUniform.Uniform4fv(1, q.uv[:])
+	} else {
+		this.texturedUniform.Uniform1i(0)
+	}
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+	this.vao.Unbind()
+	this.program.Unuse()
+	gl.Disable(gl.BLEND)
+}
+
+//fillRect draws a solid rectangle, respecting the font's clip rectangle
+func (this *Font) fillRect(x, y, w, h float32, color Vector4) {
+	restore := this.applyClip()
+	this.rects.draw(x, y, w, h, color)
+	restore()
+}
+
+//fillRoundedRect is like fillRect but with corners of the given radius in pixels
+func (this *Font) fillRoundedRect(r Rect, radius float32, color Vector4) {
+	restore := this.applyClip()
+	this.rects.drawQuad(quad{
+		rect:   r,
+		color:  color,
+		radius: radius,
+		pixels: [2]float32{r.W * this.source.width / 2, r.H * this.source.height / 2},
+	})
+	restore()
+}
+
+func (this *rectRenderer) Delete() {
+	this.program.Delete()
+	this.vbo.Delete()
+	this.vao.Delete()
+}
@@ -0,0 +1,107 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+)
+
+//PaddedRect is the area drawing the layout can touch: its Rect grown by any background padding, outline, halo and shadow
+func (this *Layout) PaddedRect() Rect {
+	r := this.Rect()
+	pad := this.style.Outline.Width
+	if this.style.Background.Color[3] > 0 && this.style.Background.Padding > pad {
+		pad = this.style.Background.Padding
+	}
+	padX, padY := pad, pad
+	if halo := this.style.Halo; halo.Width > 0 && halo.Color[3] > 0 {
+		reach := min32(halo.Width+halo.Softness, HaloSpread)
+		padX = max32(padX, reach*this.font.atlas.sx)
+		padY = max32(padY, reach*this.font.atlas.sy)
+	}
+	r = Rect{r.X - padX, r.Y + padY, r.W + 2*padX, r.H + 2*padY}
+	if this.style.Shadow.Color[3] > 0 {
+		dx, dy := this.style.Shadow.Offset[0], this.style.Shadow.Offset[1]
+		if dx < 0 {
+			r.X += dx
+		}
+		if dy > 0 {
+			r.Y += dy
+		}
+		r.W += abs32(dx)
+		r.H += abs32(dy)
+	}
+	return r
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+//RenderToTexture draws layout once into a new texture covering its PaddedRect, so that static text can afterwards
+//be drawn as a single quad with DrawTexture. the texture holds premultiplied alpha and belongs to the caller.
+func (this *Font) RenderToTexture(layout *Layout) gl.Texture {
+	_, _, w, h := this.pixelRect(layout.PaddedRect())
+	return this.renderToTexture(layout, layout.PaddedRect(), w, h)
+}
+
+//renderToTexture draws layout into a new w by h texture, with r in draw units stretched over all of it
+func (this *Font) renderToTexture(layout *Layout, r Rect, w, h int) gl.Texture {
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	tex := gl.GenTexture()
+	tex.Bind(gl.TEXTURE_2D)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, w, h, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	tex.Unbind(gl.TEXTURE_2D)
+
+	previous := make([]int32, 1)
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, previous)
+	viewport := make([]int32, 4)
+	gl.GetIntegerv(gl.VIEWPORT, viewport)
+	clearColor := make([]float32, 4)
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, clearColor)
+
+	fbo := gl.GenFramebuffer()
+	fbo.Bind()
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, tex, 0)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		logf("gltext: Render to texture framebuffer is incomplete")
+	} else {
+		gl.ClearColor(0, 0, 0, 0)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+		//the viewport is the screen, scaled and shifted so that r lands exactly on the texture
+		sw, sh := float32(w)*2/r.W, float32(h)*2/r.H
+		gl.Viewport(int(-r.X*sw/2), int(float32(h)-(2+r.Y)*sh/2), int(sw), int(sh))
+		clip := this.clip
+		this.clip = nil
+		layout.Draw()
+		this.clip = clip
+	}
+	gl.Framebuffer(previous[0]).Bind()
+	gl.Viewport(int(viewport[0]), int(viewport[1]), int(viewport[2]), int(viewport[3]))
+	gl.ClearColor(clearColor[0], clearColor[1], clearColor[2], clearColor[3])
+	fbo.Delete()
+	return tex
+}
+
+//DrawTexture draws a texture made by RenderToTexture over r, normally the layout's PaddedRect
+func (this *Font) DrawTexture(texture gl.Texture, r Rect) {
+	restore := this.applyClip()
+	this.rects.drawQuad(quad{
+		rect:          r,
+		color:         Vector4{1, 1, 1, 1},
+		texture:       texture,
+		uv:            Vector4{0, 1, 1, 0},
+		premultiplied: true,
+	})
+	restore()
+}
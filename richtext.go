@@ -0,0 +1,199 @@
+package gltext
+
+import (
+	"strconv"
+	"strings"
+)
+
+//RichFonts are the fonts the <b> and <i> tags of LayoutRich switch to; nil ones fall back to Regular
+type RichFonts struct {
+	Regular, Bold, Italic, BoldItalic *Font
+}
+
+//richSpan is a piece of rich text between tags
+type richSpan struct {
+	text         string
+	bold, italic bool
+	color        *Vector4
+	size         float32 //in pixels, or zero for the font's own
+	scale        float32 //relative to size or the font, or zero for full size
+}
+
+//richState is the tags open at a point of rich text, innermost last
+type richState struct {
+	bold, italic int
+	colors       []Vector4
+	sizes        []richSize
+}
+
+type richSize struct {
+	pixels, scale float32
+}
+
+var richEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&", "&quot;", "\"")
+
+//LayoutRich lays out s from x, y, wrapped at spaces to fit maxWidth unless it is zero, with the tags localization
+//teams tend to use: <b>, <i>, <color=#f00> or <color=red>, and <size=24> in pixels or <size=150%>, each closed by its
+//</tag>, and <br>. tags nest, unknown or malformed ones are drawn as they are, and &lt; &gt; &amp; &quot; stand for
+//< > & ". lines are broken at <br> and at line breaks.
+func LayoutRich(x, y, maxWidth float32, fonts RichFonts, s string) *RunLines {
+	this := newRunLines(x, y)
+	for _, paragraph := range parseRich(s) {
+		if len(paragraph) == 0 {
+			this.end -= fonts.Regular.LineHeight()
+			continue
+		}
+		runs := make([]Run, len(paragraph))
+		for i, span := range paragraph {
+			runs[i] = fonts.run(span)
+		}
+		this.wrap(maxWidth, 0, runs)
+	}
+	return this
+}
+
+//parseRich splits s into paragraphs of spans
+func parseRich(s string) [][]richSpan {
+	var paragraphs [][]richSpan
+	var spans []richSpan
+	var state richState
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, state.span(richEntities.Replace(text.String())))
+			text.Reset()
+		}
+	}
+	//tags stay open from one line to the next
+	for l, line := range splitLines(s) {
+		if l > 0 {
+			flush()
+			paragraphs, spans = append(paragraphs, spans), nil
+		}
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			end := -1
+			if c == '<' {
+				end = strings.IndexByte(line[i:], '>')
+			}
+			if end < 0 {
+				text.WriteByte(c)
+				continue
+			}
+			tag := line[i+1 : i+end]
+			if strings.EqualFold(tag, "br") || strings.EqualFold(tag, "br/") {
+				flush()
+				paragraphs, spans = append(paragraphs, spans), nil
+				i += end
+				continue
+			}
+			next := state
+			if !next.apply(tag) {
+				text.WriteByte(c)
+				continue
+			}
+			flush()
+			state = next
+			i += end
+		}
+	}
+	flush()
+	return append(paragraphs, spans)
+}
+
+//apply opens or closes tag, returning false if it isn't one LayoutRich knows
+func (this *richState) apply(tag string) bool {
+	name, value := tag, ""
+	if eq := strings.IndexByte(tag, '='); eq >= 0 {
+		name, value = tag[:eq], strings.Trim(tag[eq+1:], "\"'")
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "b":
+		this.bold++
+	case "i":
+		this.italic++
+	case "/b":
+		this.bold = maxInt(this.bold-1, 0)
+	case "/i":
+		this.italic = maxInt(this.italic-1, 0)
+	case "color":
+		c, err := ParseColor(value)
+		if err != nil {
+			return false
+		}
+		this.colors = append(this.colors[:len(this.colors):len(this.colors)], c)
+	case "/color":
+		if len(this.colors) > 0 {
+			this.colors = this.colors[:len(this.colors)-1]
+		}
+	case "size":
+		size, ok := parseRichSize(value)
+		if !ok {
+			return false
+		}
+		this.sizes = append(this.sizes[:len(this.sizes):len(this.sizes)], size)
+	case "/size":
+		if len(this.sizes) > 0 {
+			this.sizes = this.sizes[:len(this.sizes)-1]
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+//parseRichSize reads pixels, as "24", or a percentage of the size outside the tag, as "150%"
+func parseRichSize(s string) (richSize, bool) {
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 32)
+		return richSize{scale: float32(percent) / 100}, err == nil && percent > 0
+	}
+	pixels, err := strconv.ParseFloat(s, 32)
+	return richSize{pixels: float32(pixels)}, err == nil && pixels > 0
+}
+
+//span is text set as the tags open say
+func (this *richState) span(text string) richSpan {
+	span := richSpan{text: text, bold: this.bold > 0, italic: this.italic > 0}
+	if n := len(this.colors); n > 0 {
+		c := this.colors[n-1]
+		span.color = &c
+	}
+	//a percentage is of the size outside it, so they multiply back to the last size given in pixels
+	scale := float32(1)
+	for _, size := range this.sizes {
+		if size.pixels > 0 {
+			span.size, scale = size.pixels, 1
+		} else {
+			scale *= size.scale
+		}
+	}
+	if scale != 1 {
+		span.scale = scale
+	}
+	return span
+}
+
+//run returns the run drawing span
+func (this RichFonts) run(span richSpan) Run {
+	font := this.Regular
+	switch {
+	case span.bold && span.italic && this.BoldItalic != nil:
+		font = this.BoldItalic
+	case span.bold && this.Bold != nil:
+		font = this.Bold
+	case span.italic && this.Italic != nil:
+		font = this.Italic
+	}
+	run := Run{Font: font, Text: span.text, Scale: span.scale}
+	if span.size > 0 {
+		run.Scale = run.size() * span.size / float32(font.source.scale)
+	}
+	if span.color != nil {
+		style := font.style
+		style.Color = *span.color
+		run.Style = &style
+	}
+	return run
+}
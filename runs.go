@@ -0,0 +1,164 @@
+package gltext
+
+import (
+	"strings"
+)
+
+//Run is a piece of text in its own font, for mixing fonts and sizes on one line
+type Run struct {
+	Font  *Font
+	Style *Style  //nil for the font's own style
+	Text  string  //should not contain newlines
+	Scale float32 //size relative to the font, as Layout.Scale; zero is full size
+}
+
+func (this Run) style() Style {
+	if this.Style != nil {
+		return *this.Style
+	}
+	return this.Font.style
+}
+
+func (this Run) size() float32 {
+	if this.Scale == 0 {
+		return 1
+	}
+	return this.Scale
+}
+
+//width is the advance of s laid out as part of the run
+func (this Run) width(s string) float32 {
+	return this.Font.LayoutStyle(0, 0, this.style(), s).Width() * this.size()
+}
+
+//RunLayout is a line of runs in different fonts, placed so that their baselines line up
+type RunLayout struct {
+	Layouts []*Layout
+	x, y    float32
+	ascent  float32
+	descent float32
+}
+
+//LayoutRuns lays runs out one after another from x, y, the top of a line tall enough for the largest of them
+func LayoutRuns(x, y float32, runs ...Run) *RunLayout {
+	this := &RunLayout{x: x, y: y}
+	for _, run := range runs {
+		this.ascent = max32(this.ascent, run.Font.Ascent()*run.size())
+		this.descent = max32(this.descent, run.Font.Descent()*run.size())
+	}
+	penX := x
+	for _, run := range runs {
+		layout := run.Font.LayoutStyle(penX, y-this.ascent+run.Font.Ascent()*run.size(), run.style(), run.Text)
+		if run.Scale != 0 {
+			layout.Scale(run.Scale)
+		}
+		penX += layout.Width()
+		this.Layouts = append(this.Layouts, layout)
+	}
+	return this
+}
+
+//Baseline is the y of the shared baseline
+func (this *RunLayout) Baseline() float32 {
+	return this.y - this.ascent
+}
+
+func (this *RunLayout) Width() float32 {
+	width := float32(0)
+	for _, l := range this.Layouts {
+		width = max32(width, l.x-this.x+l.Width())
+	}
+	return width
+}
+
+func (this *RunLayout) Height() float32 {
+	return this.ascent + this.descent
+}
+
+func (this *RunLayout) Draw() {
+	for _, l := range this.Layouts {
+		l.Draw()
+	}
+}
+
+//RunLines is lines of runs, one below another
+type RunLines struct {
+	Lines []*RunLayout
+	x, y  float32
+	end   float32 //the y of the bottom of the last line
+}
+
+func newRunLines(x, y float32) *RunLines {
+	return &RunLines{x: x, y: y, end: y}
+}
+
+//WrapRuns lays runs out from x, y, breaking lines at spaces so that none is wider than maxWidth, unless it is zero.
+//lines after the first start indent to the right of x.
+func WrapRuns(x, y, maxWidth, indent float32, runs ...Run) *RunLines {
+	this := newRunLines(x, y)
+	this.wrap(maxWidth, indent, runs)
+	return this
+}
+
+//wrap adds the lines of runs below those laid out before
+func (this *RunLines) wrap(maxWidth, indent float32, runs []Run) {
+	var line []Run
+	lineX, penX := this.x, this.x
+	last := -1 //the run the last of line was split from
+	newLine := func() {
+		l := LayoutRuns(lineX, this.end, line...)
+		this.Lines = append(this.Lines, l)
+		this.end -= l.Height()
+		line, lineX, penX, last = nil, this.x+indent, this.x+indent, -1
+	}
+	for r, run := range runs {
+		for _, word := range splitWords(run.Text) {
+			if maxWidth > 0 && len(line) > 0 && penX+run.width(strings.TrimRight(word, " ")) > this.x+maxWidth {
+				newLine()
+			}
+			if last == r {
+				line[len(line)-1].Text += word
+			} else {
+				piece := run
+				piece.Text = word
+				line = append(line, piece)
+				last = r
+			}
+			penX += run.width(word)
+		}
+	}
+	if len(line) > 0 {
+		newLine()
+	}
+}
+
+//splitWords splits s after each run of spaces, so that joining the words gives s back
+func splitWords(s string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(s); i++ {
+		if s[i-1] == ' ' && s[i] != ' ' {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+	return append(words, s[start:])
+}
+
+func (this *RunLines) Width() float32 {
+	width := float32(0)
+	for _, l := range this.Lines {
+		width = max32(width, l.x-this.x+l.Width())
+	}
+	return width
+}
+
+func (this *RunLines) Height() float32 {
+	return this.y - this.end
+}
+
+func (this *RunLines) Draw() {
+	for _, l := range this.Lines {
+		l.Draw()
+	}
+}
@@ -0,0 +1,109 @@
+package gltext
+
+import (
+	"bytes"
+	"sync"
+	"unicode/utf8"
+)
+
+//ScreenWriter is an io.Writer that shows what is written to it in a region of the screen, the newest lines at the
+//bottom and older ones scrolling up out of it, so fmt.Fprintf or a log.Logger can be pointed at the display.
+//it may be written from any goroutine; Draw it on the GL thread.
+type ScreenWriter struct {
+	X, Y, Width float32
+	Rows        int //number of lines shown
+	Style       Style
+
+	font    *Font
+	mu      sync.Mutex
+	lines   []string
+	head    int //index of the oldest line once lines is full
+	count   int
+	partial []byte //written since the last line break, shown as the last line
+}
+
+//NewScreenWriter shows rows lines below x, y, keeping the last capacity of them
+func NewScreenWriter(font *Font, x, y, width float32, rows, capacity int) *ScreenWriter {
+	return &ScreenWriter{X: x, Y: y, Width: width, Rows: rows, Style: font.style, font: font, lines: make([]string, capacity)}
+}
+
+//maxPartialLine is the most of a line kept waiting for its line break; far more than fits across the screen
+const maxPartialLine = 4096
+
+//Write adds p to what is shown, a line at a time; it never fails. text that runs past maxPartialLine bytes without a
+//line break is broken into lines of that length, so a writer that never ends its lines doesn't grow without bound.
+func (this *ScreenWriter) Write(p []byte) (int, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.partial = append(this.partial, p...)
+	if end := bytes.LastIndexByte(this.partial, '\n'); end >= 0 {
+		for _, line := range splitLines(trimCR(string(this.partial[:end]))) {
+			this.append(line)
+		}
+		this.partial = append(this.partial[:0], this.partial[end+1:]...)
+	}
+	for len(this.partial) > maxPartialLine {
+		cut := maxPartialLine
+		for cut > 0 && !utf8.RuneStart(this.partial[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = maxPartialLine
+		}
+		this.append(string(this.partial[:cut]))
+		this.partial = append(this.partial[:0], this.partial[cut:]...)
+	}
+	return len(p), nil
+}
+
+func (this *ScreenWriter) append(line string) {
+	capacity := len(this.lines)
+	if capacity == 0 {
+		return
+	}
+	if this.count < capacity {
+		this.lines[(this.head+this.count)%capacity] = line
+		this.count++
+	} else {
+		this.lines[this.head] = line
+		this.head = (this.head + 1) % capacity
+	}
+}
+
+func (this *ScreenWriter) Clear() {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.head, this.count, this.partial = 0, 0, this.partial[:0]
+}
+
+//shown returns the lines to draw, oldest first
+func (this *ScreenWriter) shown() []string {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	rows := this.Rows
+	if len(this.partial) > 0 {
+		rows--
+	}
+	lines := make([]string, 0, this.Rows)
+	for i := maxInt(this.count-rows, 0); i < this.count; i++ {
+		lines = append(lines, this.lines[(this.head+i)%len(this.lines)])
+	}
+	if len(this.partial) > 0 && this.Rows > 0 {
+		lines = append(lines, string(this.partial))
+	}
+	return lines
+}
+
+func (this *ScreenWriter) Rect() Rect {
+	return Rect{this.X, this.Y, this.Width, float32(this.Rows) * this.font.cellHeight}
+}
+
+//Draw draws the last Rows lines, clipped to Width
+func (this *ScreenWriter) Draw() {
+	lines := this.shown()
+	this.font.withClip(this.Rect(), func() {
+		for i, line := range lines {
+			this.font.print(this.X, this.Y-float32(i)*this.font.cellHeight, line, this.Style)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package gltext
+
+//Rect is an axis aligned rectangle in draw units; X, Y is the top left corner and H extends downwards
+type Rect struct {
+	X, Y, W, H float32
+}
+
+func (this Rect) Contains(x, y float32) bool {
+	return x >= this.X && x < this.X+this.W && y <= this.Y && y > this.Y-this.H
+}
+
+//Intersect returns the overlap of two rectangles, which is empty if they do not overlap
+func (this Rect) Intersect(other Rect) Rect {
+	left := max32(this.X, other.X)
+	right := min32(this.X+this.W, other.X+other.W)
+	top := min32(this.Y, other.Y)
+	bottom := max32(this.Y-this.H, other.Y-other.H)
+	if right <= left || top <= bottom {
+		return Rect{left, top, 0, 0}
+	}
+	return Rect{left, top, right - left, top - bottom}
+}
+
+//Union returns the smallest rectangle containing both; an empty rectangle contributes nothing
+func (this Rect) Union(other Rect) Rect {
+	if this.W <= 0 && this.H <= 0 {
+		return other
+	}
+	if other.W <= 0 && other.H <= 0 {
+		return this
+	}
+	left := min32(this.X, other.X)
+	right := max32(this.X+this.W, other.X+other.W)
+	top := max32(this.Y, other.Y)
+	bottom := min32(this.Y-this.H, other.Y-other.H)
+	return Rect{left, top, right - left, top - bottom}
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+//SelectionRects returns one rectangle per line covering the runes in [start, end)
+func (this *Layout) SelectionRects(start, end int) []Rect {
+	if start > end {
+		start, end = end, start
+	}
+	rects := make([]Rect, 0)
+	for _, line := range this.lines {
+		first, last := line.first, line.end
+		if start > first {
+			first = start
+		}
+		if end < last {
+			last = end
+		}
+		if first >= last {
+			continue
+		}
+		x1, _, _ := this.CaretPos(first)
+		g := this.glyphs[last-1]
+		x2 := g.x + g.advance
+		rects = append(rects, Rect{x1, line.y, x2 - x1, this.lineHeight()})
+	}
+	return rects
+}
+
+//DrawSelection fills the selection rectangles for [start, end); call it before Draw so the text stays on top
+func (this *Layout) DrawSelection(start, end int, color Vector4) {
+	for _, r := range this.SelectionRects(start, end) {
+		this.font.fillRect(r.X, r.Y, r.W, r.H, color)
+	}
+}
+
+//Highlight marks the runes in [start, end) to be drawn over a background of the given color, such as for search results
+func (this *Layout) Highlight(start, end int, color Vector4) {
+	this.highlights = append(this.highlights, highlight{start, end, color, false})
+}
+
+func (this *Layout) ClearHighlights() {
+	this.highlights = nil
+}
@@ -0,0 +1,151 @@
+package gltext
+
+import (
+	"container/list"
+	"strings"
+	"unicode/utf8"
+)
+
+//ShapeCacheSize bounds how many shaped words each font remembers
+var ShapeCacheSize = 1024
+
+//shapedGlyph is the part of a layoutGlyph that depends only on the rune, its neighbours in the word and the style
+type shapedGlyph struct {
+	index   int
+	advance float32
+	scale   float32
+	inset   float32
+	missing bool //the font has no glyph for the rune
+}
+
+//ShapeCacheStats counts how often laying out text found its words already shaped
+type ShapeCacheStats struct {
+	Hits, Misses, Evictions int
+	Entries                 int
+}
+
+//shapeKey is a word and the style options that change how it is shaped
+type shapeKey struct {
+	text           string
+	transform      Transform
+	ligatures      bool
+	tabularFigures bool
+	tracking       float32
+}
+
+//shapeCache is a least recently used cache of shaped words, so scenes full of repeated words don't look up
+//every glyph and ligature again
+type shapeCache struct {
+	entries map[shapeKey]*list.Element
+	order   *list.List //front is most recently used
+	stats   ShapeCacheStats
+}
+
+type shapeEntry struct {
+	key    shapeKey
+	glyphs []shapedGlyph
+}
+
+//segmentBreaks are runes shaped on their own, which split text into the words that are cached
+const segmentBreaks = " \t\n\r\u2028\u2029\uFFFC"
+
+//nextSegment returns the word at the start of s, or its first rune if that is a break
+func nextSegment(s string) string {
+	end := strings.IndexAny(s, segmentBreaks)
+	switch end {
+	case -1:
+		return s
+	case 0:
+		_, size := utf8.DecodeRuneInString(s)
+		return s[:size]
+	}
+	return s[:end]
+}
+
+//shape returns one shapedGlyph per rune of s, from the cache if it has been shaped before in the same way
+func (this *Font) shape(s string, style Style) []shapedGlyph {
+	if ShapeCacheSize <= 0 {
+		return this.shapeUncached(s, style)
+	}
+	c := &this.shapes
+	if c.entries == nil {
+		c.entries = make(map[shapeKey]*list.Element)
+		c.order = list.New()
+	}
+	key := shapeKey{s, style.Transform, style.Ligatures, style.TabularFigures, style.Tracking}
+	if e, ok := c.entries[key]; ok {
+		c.stats.Hits++
+		c.order.MoveToFront(e)
+		glyphs := e.Value.(*shapeEntry).glyphs
+		//count the glyphs as used, so that the rest of the layout doesn't evict them
+		for _, g := range glyphs {
+			if g.index >= 0 {
+				this.atlas.touch(g.index)
+			}
+		}
+		return glyphs
+	}
+	c.stats.Misses++
+	generation := this.generation
+	glyphs := this.shapeUncached(s, style)
+	//loading a glyph evicted another and cleared the cache, so the word belongs to no generation of it
+	if this.generation != generation {
+		return glyphs
+	}
+	c.entries[key] = c.order.PushFront(&shapeEntry{key, glyphs})
+	for c.order.Len() > ShapeCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*shapeEntry).key)
+		c.stats.Evictions++
+	}
+	return glyphs
+}
+
+func (this *Font) shapeUncached(s string, style Style) []shapedGlyph {
+	glyphs := make([]shapedGlyph, 0, len(s))
+	ligature := pendingLigature{}
+	for i, ch := range s {
+		g := shapedGlyph{index: -1}
+		shown, scale := style.Transform.apply(ch)
+		if ligature.remaining > 0 {
+			//a later rune of a ligature isn't drawn, but takes a share of its advance so that carets can sit inside it
+			g.advance = ligature.share
+			ligature.remaining--
+		} else if isZeroWidth(ch) {
+			//neither drawn nor advanced past, but not missing either
+		} else if index, n, ok := this.ligatureAt(s[i:], style); ok {
+			g.index = index
+			g.advance = this.atlas.offsets[index] / float32(n)
+			ligature = pendingLigature{remaining: n - 1, share: g.advance}
+		} else if index, err := this.glyphIndex(shown); err == nil {
+			g.index = index
+			g.scale = scale
+			size := layoutGlyph{scale: scale}.drawScale()
+			g.advance = this.atlas.offsets[index] * size
+			if style.TabularFigures && shown >= '0' && shown <= '9' {
+				//centre the digit in the widest digit's advance
+				width := this.digitWidth() * size
+				g.inset = (width - g.advance) / 2
+				g.advance = width
+			}
+			g.advance += style.Tracking
+		} else {
+			g.missing = true
+		}
+		glyphs = append(glyphs, g)
+	}
+	return glyphs
+}
+
+//ShapeCacheStats reports how well the font's shape cache is doing
+func (this *Font) ShapeCacheStats() ShapeCacheStats {
+	stats := this.shapes.stats
+	stats.Entries = len(this.shapes.entries)
+	return stats
+}
+
+//clear forgets every shaped word, for when glyph metrics change, but keeps the counts
+func (this *shapeCache) clear() {
+	this.entries, this.order = make(map[shapeKey]*list.Element), list.New()
+}
@@ -0,0 +1,113 @@
+package gltext
+
+import (
+	"sort"
+)
+
+//Span is a token from a syntax highlighter: the runes [Start, End) of a layout and how they are drawn
+type Span struct {
+	Start, End int
+	Style      SpanStyle
+}
+
+//SpanStyle is what a span can change about its runes without the layout being shaped again
+type SpanStyle struct {
+	Color       Vector4 //a fully transparent color leaves the layout's own color in place
+	Background  Vector4 //drawn behind the span like a Highlight, unless fully transparent
+	Decorations Decoration
+}
+
+//SetSpans restyles the layout with spans, replacing those set before. nothing is laid out again, so it is cheap
+//enough to call whenever a highlighter has new tokens. links keep their own colors over any span.
+func (this *Layout) SetSpans(spans []Span) {
+	for i := range this.glyphs {
+		this.glyphs[i].color = nil
+	}
+	highlights := this.highlights[:0]
+	for _, h := range this.highlights {
+		if !h.span {
+			highlights = append(highlights, h)
+		}
+	}
+	this.highlights = highlights
+	this.spans = append(this.spans[:0], spans...)
+	for _, s := range spans {
+		if s.Style.Color[3] > 0 {
+			c := s.Style.Color
+			for g := maxInt(s.Start, 0); g < s.End && g < len(this.glyphs); g++ {
+				this.glyphs[g].color = &c
+			}
+		}
+		if s.Style.Background[3] > 0 {
+			this.highlights = append(this.highlights, highlight{s.Start, s.End, s.Style.Background, true})
+		}
+	}
+	for i := range this.links {
+		color := this.LinkStyle.Color
+		if i == this.hovered {
+			color = this.LinkStyle.HoverColor
+		}
+		if color[3] > 0 {
+			this.colorLink(i, color)
+		}
+	}
+}
+
+func (this *Layout) Spans() []Span {
+	return this.spans
+}
+
+//RuneIndex returns the index of the rune at byteOffset of the laid out string, for highlighters that report tokens
+//by byte
+func (this *Layout) RuneIndex(byteOffset int) int {
+	return sort.Search(len(this.glyphs), func(i int) bool {
+		return this.glyphs[i].byteOffset >= byteOffset
+	})
+}
+
+//spanColor is the color of the last span over glyph g, or nil
+func (this *Layout) spanColor(g int) *Vector4 {
+	for i := len(this.spans) - 1; i >= 0; i-- {
+		if s := this.spans[i]; g >= s.Start && g < s.End && s.Style.Color[3] > 0 {
+			c := s.Style.Color
+			return &c
+		}
+	}
+	return nil
+}
+
+//spanDecorations returns the underlines and strikethroughs of the spans, and the color of each. the opacity of a span's
+//own color is scaled by alpha; style's has been already, as the text it falls back to is drawn in it.
+func (this *Layout) spanDecorations(style Style, alpha float32) ([]Rect, []Vector4) {
+	var rects []Rect
+	var colors []Vector4
+	baseline := this.baseline()
+	thickness := baseline / 12
+	for _, s := range this.spans {
+		if s.Style.Decorations == 0 {
+			continue
+		}
+		color := s.Style.Color
+		if color[3] == 0 {
+			color = style.Color
+		} else {
+			color[3] *= alpha
+		}
+		for _, r := range this.SelectionRects(s.Start, s.End) {
+			if s.Style.Decorations&Underline != 0 {
+				rects, colors = append(rects, Rect{r.X, r.Y - baseline - thickness, r.W, thickness}), append(colors, color)
+			}
+			if s.Style.Decorations&Strikethrough != 0 {
+				rects, colors = append(rects, Rect{r.X, r.Y - baseline*0.65, r.W, thickness}), append(colors, color)
+			}
+		}
+	}
+	return rects, colors
+}
+
+func (this *Layout) drawSpanDecorations(style Style, alpha float32) {
+	rects, colors := this.spanDecorations(style, alpha)
+	for i, r := range rects {
+		this.font.rects.draw(r.X, r.Y, r.W, r.H, colors[i])
+	}
+}
@@ -0,0 +1,57 @@
+package gltext
+
+import (
+	"reflect"
+)
+
+//DrawStats counts what a font has drawn and uploaded since its stats were last reset, to show what the text layer costs
+type DrawStats struct {
+	Strings       int //layouts and numbers drawn
+	Glyphs        int
+	DrawCalls     int //glyph and rectangle draws, an indirect multi-draw counting once
+	ProgramBinds  int
+	TextureBinds  int
+	BytesUploaded int //vertex, index, parameter and texture data sent to the GPU
+
+	LayoutCacheHits, LayoutCacheMisses int
+	ShapeCacheHits, ShapeCacheMisses   int
+}
+
+//DrawStats reports the font's counts since ResetDrawStats, or since it was made
+func (this *Font) DrawStats() DrawStats {
+	stats := this.stats
+	stats.DrawCalls = this.calls.draws
+	stats.ProgramBinds = this.calls.programs
+	stats.TextureBinds = this.calls.textures
+	shapes := this.shapes.stats
+	stats.ShapeCacheHits = shapes.Hits - this.shapesAtReset.Hits
+	stats.ShapeCacheMisses = shapes.Misses - this.shapesAtReset.Misses
+	return stats
+}
+
+//ResetDrawStats zeroes the font's counts, usually at the start of each frame
+func (this *Font) ResetDrawStats() {
+	this.stats = DrawStats{}
+	this.calls = drawCalls{}
+	this.shapesAtReset = this.shapes.stats
+}
+
+//batchBytes is the size of the batch as uploaded
+func (this *Font) batchBytes() int {
+	switch this.path {
+	case GlyphStorage:
+		return int(reflect.TypeOf(glyphRecord{}).Size()) * len(this.storage.records)
+	case GlyphPoints:
+		return int(reflect.TypeOf(glyphPoint{}).Size()) * len(this.points.points)
+	}
+	return int(reflect.TypeOf(batchVertex{}).Size()) * len(this.batch)
+}
+
+//pageBytes is the size of atlas page p as uploaded
+func (this *Font) pageBytes(p int) int {
+	img := this.atlas.pages[p]
+	if this.compression != CompressNone {
+		return (img.Bounds().Dx() + 3) / 4 * ((img.Bounds().Dy() + 3) / 4) * 8
+	}
+	return len(img.Pix)
+}
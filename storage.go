@@ -0,0 +1,260 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"reflect"
+)
+
+//GlyphPath is how a batch of glyphs reaches the GPU and becomes quads
+type GlyphPath int
+
+const (
+	GlyphVertices GlyphPath = iota //four vertices streamed for each glyph
+	GlyphStorage                   //one record per glyph in a shader storage buffer, expanded by gl_VertexID; needs OpenGL 4.3
+	GlyphPoints                    //one point per glyph, expanded to its quad by a geometry shader
+)
+
+//the shader storage bindings of the glyph records, the atlas quads and, in place of the uniform buffer, the DrawParams
+const (
+	glyphStorageBinding  = 1
+	quadStorageBinding   = 2
+	paramsStorageBinding = 3
+)
+
+//drawIndexAttrib is the instanced attribute through which each indirect draw's base instance picks out its DrawParams
+const drawIndexAttrib = gl.AttribLocation(4)
+
+//drawElementsCommand is the layout of an indirect indexed draw
+type drawElementsCommand struct {
+	count, instanceCount, firstIndex, baseVertex, baseInstance uint32
+}
+
+//glyphRecord is one glyph of a batch on the storage path, laid out as GlyphRecord in std430
+type glyphRecord struct {
+	x, y, scale, angle float32
+	glyph, atlas       float32
+	colorIndex         float32
+	_                  float32
+}
+
+//glyphStorage holds the buffers of the storage path. its vertex array has the quad indices and drawIndex alone.
+type glyphStorage struct {
+	vao       gl.VertexArray
+	glyphs    gl.Buffer
+	quads     gl.Buffer
+	records   []glyphRecord
+	indirect  gl.Buffer
+	commands  []drawElementsCommand
+	instances gl.Buffer //0, 1, 2... for drawIndex
+	drawIndex int       //how many values instances holds
+	dsa       bool
+}
+
+//SetGlyphPath rebuilds the glyph program for path. it returns ErrGlyphPathUnsupported, leaving the path as it was,
+//if the driver is too old for it.
+func (this *Font) SetGlyphPath(path GlyphPath) error {
+	if path == this.path {
+		return nil
+	}
+	if path == GlyphStorage && !glVersionAtLeast(4, 3) {
+		return ErrGlyphPathUnsupported
+	}
+	this.program.Delete()
+	this.program = createProgram(path)
+	this.path = path
+	//DrawParams records are read from a shader storage array on the storage path, instead of bound as uniform buffer ranges
+	this.paramStride = drawParamsStride()
+	if path == GlyphStorage {
+		this.paramStride = drawParamsSize / 4
+	}
+	this.initProgram()
+	if path == GlyphStorage && this.storage == nil {
+		this.storage = newGlyphStorage(this.ebo, this.dsa)
+		this.packQuads()
+	}
+	if path == GlyphPoints && this.points == nil {
+		this.points = newGlyphPoints(this.dsa)
+	}
+	//the halo's uniforms were set on the old program
+	if this.halo != nil {
+		this.halo.texture.Delete()
+		this.halo = nil
+	}
+	return nil
+}
+
+func (this *Font) GlyphPath() GlyphPath {
+	return this.path
+}
+
+func newGlyphStorage(ebo gl.Buffer, dsa bool) *glyphStorage {
+	vao := gl.GenVertexArray()
+	vao.Bind()
+	ebo.Bind(gl.ELEMENT_ARRAY_BUFFER)
+	instances := gl.GenBuffer()
+	instances.Bind(gl.ARRAY_BUFFER)
+	drawIndexAttrib.AttribPointer(1, gl.FLOAT, false, 0, nil)
+	drawIndexAttrib.AttribDivisor(1)
+	drawIndexAttrib.EnableArray()
+	instances.Unbind(gl.ARRAY_BUFFER)
+	vao.Unbind()
+	return &glyphStorage{vao: vao, glyphs: newBuffer(dsa), quads: newBuffer(dsa), indirect: newBuffer(dsa), instances: instances, dsa: dsa}
+}
+
+//upload replaces the glyph records on the GPU with those batched
+func (this *glyphStorage) upload() {
+	bufferData(this.dsa, this.glyphs, gl.SHADER_STORAGE_BUFFER, int(reflect.TypeOf(glyphRecord{}).Size())*len(this.records), &this.records[0], gl.STREAM_DRAW)
+}
+
+//uploadQuads replaces the atlas quads, four corners of position and texture coordinates for each glyph
+func (this *glyphStorage) uploadQuads(coords []Vector4) {
+	if len(coords) == 0 {
+		return
+	}
+	bufferData(this.dsa, this.quads, gl.SHADER_STORAGE_BUFFER, 16*len(coords), &coords[0], gl.STATIC_DRAW)
+}
+
+//bind readies the storage path for drawing
+func (this *glyphStorage) bind() {
+	this.vao.Bind()
+	this.glyphs.BindBufferBase(gl.SHADER_STORAGE_BUFFER, glyphStorageBinding)
+	this.quads.BindBufferBase(gl.SHADER_STORAGE_BUFFER, quadStorageBinding)
+}
+
+//drawIndirect draws the queued passes of font with one multi-draw for each stretch of them using the same texture
+func (this *glyphStorage) drawIndirect(font *Font) {
+	if n := len(font.params) / font.paramStride; n > this.drawIndex {
+		this.growDrawIndex(n)
+	}
+	this.commands = this.commands[:0]
+	for _, d := range font.draws {
+		this.commands = append(this.commands, drawElementsCommand{uint32(6 * d.count), 1, uint32(6 * d.first), 0, uint32(d.params)})
+	}
+	size := int(reflect.TypeOf(drawElementsCommand{}).Size())
+	bufferData(this.dsa, this.indirect, gl.DRAW_INDIRECT_BUFFER, size*len(this.commands), &this.commands[0], gl.STREAM_DRAW)
+	font.stats.BytesUploaded += size * len(this.commands)
+	this.indirect.Bind(gl.DRAW_INDIRECT_BUFFER)
+	for start := 0; start < len(font.draws); {
+		end := start + 1
+		for end < len(font.draws) && font.draws[end].page == font.draws[start].page {
+			end++
+		}
+		font.bindDrawTexture(font.draws[start].page)
+		gl.MultiDrawElementsIndirect(gl.TRIANGLES, gl.UNSIGNED_INT, uintptr(size*start), end-start, 0)
+		font.calls.draws++
+		start = end
+	}
+	this.indirect.Unbind(gl.DRAW_INDIRECT_BUFFER)
+}
+
+//growDrawIndex fills instances with the indices of at least n DrawParams records
+func (this *glyphStorage) growDrawIndex(n int) {
+	n = maxInt(maxInt(n, 2*this.drawIndex), 64)
+	values := make([]float32, n)
+	for i := range values {
+		values[i] = float32(i)
+	}
+	bufferData(this.dsa, this.instances, gl.ARRAY_BUFFER, 4*n, values, gl.STATIC_DRAW)
+	this.drawIndex = n
+}
+
+func (this *glyphStorage) Delete() {
+	this.vao.Delete()
+	this.glyphs.Delete()
+	this.quads.Delete()
+	this.indirect.Delete()
+	this.instances.Delete()
+}
+
+//shaderInputs returns the GLSL version of the glyph program for the path, the declarations of the inputs of the stage
+//that places each corner, and the main function of that stage, which calls placeCorner
+func (this GlyphPath) shaderInputs() (string, string, string) {
+	switch this {
+	case GlyphStorage:
+		return "#version 430\n", `
+    struct GlyphRecord {
+        vec4 placement;
+        vec3 glyphIndex;
+        float pad;
+    };
+    layout(std430, binding = 1) readonly buffer Glyphs {
+        GlyphRecord glyphs[];
+    };
+    layout(std430, binding = 2) readonly buffer Quads {
+        vec4 quads[]; //position and texture coordinates of each corner of each atlas glyph
+    };
+    vec2 position;
+    vec2 texcoord;
+    vec4 placement;
+    vec3 glyphIndex;`, `
+    void main() {
+        loadParams();
+        GlyphRecord g = glyphs[gl_VertexID / 4];
+        vec4 corner = quads[int(g.glyphIndex.y) * 4 + gl_VertexID % 4];
+        position = corner.xy;
+        texcoord = corner.zw;
+        placement = g.placement;
+        glyphIndex = g.glyphIndex;
+        placeCorner(gl_VertexID % 4);
+    }`
+	case GlyphPoints:
+		return "#version 150\n", pointGeometryInputs, pointGeometryMain
+	}
+	return "#version 150\n", `
+    in vec2 position;
+    in vec2 texcoord;
+    in vec4 placement; //where the glyph's quad is drawn from, its scale and the angle it is turned through about its baseline origin
+    in vec3 glyphIndex; //the glyph's index in its layout and in the atlas, and its indexed color`, `
+    void main() {
+        placeCorner(gl_VertexID % 4);
+    }`
+}
+
+//drawParams declares the DrawParams of the pass being drawn to the vertex or fragment shader, and loadParams, which
+//main must call before reading them
+func (this GlyphPath) drawParams(fragment bool) string {
+	if this != GlyphStorage {
+		return `
+    layout(std140) uniform DrawParams {
+        vec4 color;
+        vec4 animation; //time, amplitude, speed, spread
+        vec2 offset;
+        int effects;
+    };
+    void loadParams() {}`
+	}
+	params := `
+    struct Params {
+        vec4 color;
+        vec4 animation; //time, amplitude, speed, spread
+        vec2 offset;
+        int effects;
+    };
+    layout(std430, binding = 3) readonly buffer DrawParams {
+        Params drawParams[];
+    };
+    vec4 color;
+    vec4 animation;
+    vec2 offset;
+    int effects;`
+	if fragment {
+		return params + `
+    flat in int paramsIndex;
+    void loadParams() {
+        Params p = drawParams[paramsIndex];
+        color = p.color;
+        effects = p.effects;
+    }`
+	}
+	return params + `
+    in float drawIndex; //the base instance of the indirect draw
+    flat out int paramsIndex;
+    void loadParams() {
+        paramsIndex = int(drawIndex);
+        Params p = drawParams[paramsIndex];
+        color = p.color;
+        animation = p.animation;
+        offset = p.offset;
+        effects = p.effects;
+    }`
+}
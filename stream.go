@@ -0,0 +1,101 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+	"reflect"
+	"unsafe"
+)
+
+//streamRegions is how many regions the persistently mapped vertex buffer is split into: the CPU fills one while the
+//GPU may still be drawing from the other two
+const streamRegions = 3
+
+//streamGlyphs is how many glyphs a region holds to begin with. it grows to fit the largest batch.
+const streamGlyphs = 4096
+
+const streamFlags = gl.MAP_WRITE_BIT | gl.MAP_PERSISTENT_BIT | gl.MAP_COHERENT_BIT
+
+//vertexStream is the vertex buffer of the GlyphVertices path from OpenGL 4.4: mapped once for good, and filled a region
+//at a time. moving off a region fences it, and it is not written again until the GPU has passed the fence,
+//so batches are streamed without orphaning the buffer or mapping it each time.
+type vertexStream struct {
+	buffer  gl.Buffer
+	mapped  []byte
+	region  int //bytes in each region, a whole number of vertices
+	current int
+	used    int //bytes written to the current region
+	fences  [streamRegions]gl.Sync
+}
+
+func newVertexStream(glyphs int) *vertexStream {
+	region := 4 * glyphs * int(reflect.TypeOf(batchVertex{}).Size())
+	buffer := gl.GenBuffer()
+	buffer.Bind(gl.ARRAY_BUFFER)
+	gl.BufferStorage(gl.ARRAY_BUFFER, streamRegions*region, nil, streamFlags)
+	p := gl.MapBufferRange(gl.ARRAY_BUFFER, 0, streamRegions*region, streamFlags)
+	buffer.Unbind(gl.ARRAY_BUFFER)
+	return &vertexStream{buffer: buffer, mapped: (*[1 << 30]byte)(p)[: streamRegions*region : streamRegions*region], region: region}
+}
+
+//write copies data into the stream and returns its offset in the buffer, or false if data is larger than a region
+func (this *vertexStream) write(data []byte) (int, bool) {
+	if len(data) > this.region {
+		return 0, false
+	}
+	if this.used+len(data) > this.region {
+		this.fences[this.current] = gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+		this.current = (this.current + 1) % streamRegions
+		this.used = 0
+		this.wait(this.current)
+	}
+	offset := this.current*this.region + this.used
+	copy(this.mapped[offset:], data)
+	this.used += len(data)
+	return offset, true
+}
+
+//wait blocks until the GPU has finished with region r
+func (this *vertexStream) wait(r int) {
+	fence := this.fences[r]
+	if fence == 0 {
+		return
+	}
+	for gl.ClientWaitSync(fence, gl.SYNC_FLUSH_COMMANDS_BIT, 1e6) == gl.TIMEOUT_EXPIRED {
+		//the region was fenced two regions ago, so this is rare and short
+	}
+	gl.DeleteSync(fence)
+	this.fences[r] = 0
+}
+
+func (this *vertexStream) Delete() {
+	for r := range this.fences {
+		if this.fences[r] != 0 {
+			gl.DeleteSync(this.fences[r])
+		}
+	}
+	this.buffer.Bind(gl.ARRAY_BUFFER)
+	gl.UnmapBuffer(gl.ARRAY_BUFFER)
+	this.buffer.Unbind(gl.ARRAY_BUFFER)
+	this.buffer.Delete()
+}
+
+//setStream replaces the font's vertex stream with one of regions of at least glyphs, pointing the vertex array at it
+func (this *Font) setStream(glyphs int) {
+	if this.stream != nil {
+		this.stream.Delete()
+	}
+	this.stream = newVertexStream(glyphs)
+	this.vao.update()
+}
+
+//streamBatch writes the batch into the vertex stream, and has the next flush draw from where it was written
+func (this *Font) streamBatch() {
+	size := int(reflect.TypeOf(batchVertex{}).Size())
+	data := (*[1 << 30]byte)(unsafe.Pointer(&this.batch[0]))[: size*len(this.batch) : size*len(this.batch)]
+	offset, ok := this.stream.write(data)
+	if !ok {
+		this.setStream(maxInt(len(this.batch)/4, 2*this.stream.region/(4*size)))
+		offset, _ = this.stream.write(data)
+	}
+	this.baseVertex = offset / size
+}
@@ -0,0 +1,108 @@
+package gltext
+
+//Style bundles the appearance of drawn text so it can be built once and passed to PrintfStyle
+type Style struct {
+	Color       Vector4
+	Tracking    float32 //extra space added after every glyph, in draw units
+	Decorations Decoration
+	Shadow      Shadow
+	Outline     Outline
+	Halo        Halo
+	Animation   Animation
+	Background  Background
+	TabStops    []TabStop //in increasing order of position
+	Paragraph   Paragraph
+	Transform   Transform
+	Ligatures   bool //join fi, fl and friends into single glyphs where possible
+	//TabularFigures gives every digit the advance of the widest one, so that changing numbers don't shift sideways
+	TabularFigures bool
+	//Normalize composes text to Unicode NFC before it is laid out, so decomposed sequences such as those of macOS file
+	//names are drawn with the font's precomposed glyphs. the byte offsets of a layout are then into the normalized text.
+	Normalize bool
+	Controls  ControlPolicy
+	//VerticalAlign says which part of the text the y passed to Printf and friends refers to
+	VerticalAlign VerticalAlign
+	//PaletteColor, when set, names the entry of the font's palette that is drawn in place of Color
+	PaletteColor string
+	//IndexedColor draws each glyph in the entry of the font's indexed colors chosen by its layout's SetColorIndex, times
+	//Color, as 8 and 16 bit games recolor text
+	IndexedColor bool
+}
+
+//Paragraph controls the shape of paragraphs, which are separated by every line break but LineSeparator.
+//FirstLineIndent applies to the first line of each paragraph and HangingIndent to the lines after it that were wrapped by LayoutWrapped or LineSeparator,
+//so a bullet list uses a HangingIndent equal to the width of the bullet. SpaceBefore and SpaceAfter separate paragraphs.
+type Paragraph struct {
+	FirstLineIndent float32
+	HangingIndent   float32
+	SpaceBefore     float32
+	SpaceAfter      float32
+}
+
+type Decoration int
+
+const (
+	Underline Decoration = 1 << iota
+	Strikethrough
+)
+
+//Shadow draws a copy of the text behind it; it is disabled while Color is fully transparent
+type Shadow struct {
+	Offset [2]float32
+	Color  Vector4
+}
+
+//Outline surrounds every glyph with Color; it is disabled while Width is zero
+type Outline struct {
+	Width float32
+	Color Vector4
+}
+
+//Background fills the measured bounds of the text, grown by Padding on every side; it is disabled while Color is fully transparent.
+//Radius rounds the corners, in pixels. A NinePatch is drawn tinted by Color instead of a solid fill.
+type Background struct {
+	Color     Vector4
+	Padding   float32
+	Radius    float32
+	NinePatch *NinePatch
+}
+
+var outlineDirections = [][2]float32{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+//withAlpha scales the opacity of every color in the style
+func (this Style) withAlpha(alpha float32) Style {
+	this.Color[3] *= alpha
+	this.Shadow.Color[3] *= alpha
+	this.Outline.Color[3] *= alpha
+	this.Halo.Color[3] *= alpha
+	this.Background.Color[3] *= alpha
+	return this
+}
+
+func DefaultStyle() Style {
+	return Style{Color: Vector4{1, 1, 1, 1}}
+}
+
+//SetStyle changes the style used by Printf
+func (this *Font) SetStyle(style Style) {
+	this.style = style
+}
+
+func (this *Font) Style() Style {
+	return this.style
+}
+
+//digitWidth is the advance of the font's widest digit
+func (this *Font) digitWidth() float32 {
+	width := float32(0)
+	for d := '0'; d <= '9'; d++ {
+		if index, err := this.glyphIndex(d); err == nil && this.atlas.offsets[index] > width {
+			width = this.atlas.offsets[index]
+		}
+	}
+	return width
+}
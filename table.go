@@ -0,0 +1,139 @@
+package gltext
+
+//Align positions text horizontally within a column or at a tab stop
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+	AlignDecimal //the first '.' lines up, or the end of the text if it has none
+)
+
+//Column describes one column of a Table; a zero Width fits the widest cell
+type Column struct {
+	Align Align
+	Width float32
+}
+
+//Table lays out rows of cells in aligned columns, for scoreboards and debug tables
+type Table struct {
+	Columns     []Column
+	Gap         float32 //space between columns, in draw units
+	Style       Style
+	HeaderStyle Style //used for the first row when Header is set
+	Header      bool
+
+	font *Font
+	rows [][]string
+}
+
+func NewTable(font *Font, columns ...Column) *Table {
+	return &Table{
+		Columns:     columns,
+		Gap:         font.cellHeight / 2,
+		Style:       font.style,
+		HeaderStyle: font.style,
+		font:        font,
+	}
+}
+
+//AddRow appends a row; missing cells are left empty and extra cells are ignored
+func (this *Table) AddRow(cells ...string) {
+	this.rows = append(this.rows, cells)
+}
+
+func (this *Table) Clear() {
+	this.rows = this.rows[:0]
+}
+
+func (this *Table) rowStyle(row int) Style {
+	if row == 0 && this.Header {
+		return this.HeaderStyle
+	}
+	return this.Style
+}
+
+//cells lays out every cell at the origin and returns the resolved column widths, and for decimal columns the position of the decimal point
+func (this *Table) cells() ([][]*Layout, []float32, []float32) {
+	widths := make([]float32, len(this.Columns))
+	points := make([]float32, len(this.Columns))
+	after := make([]float32, len(this.Columns))
+	for i, c := range this.Columns {
+		widths[i] = c.Width
+	}
+	layouts := make([][]*Layout, len(this.rows))
+	for r, row := range this.rows {
+		layouts[r] = make([]*Layout, len(this.Columns))
+		for c := range this.Columns {
+			if c >= len(row) {
+				continue
+			}
+			cell := this.font.LayoutStyle(0, 0, this.rowStyle(r), row[c])
+			layouts[r][c] = cell
+			if this.Columns[c].Align == AlignDecimal {
+				point := cell.decimalOffset()
+				points[c] = max32(points[c], point)
+				after[c] = max32(after[c], cell.Width()-point)
+			} else if this.Columns[c].Width == 0 && cell.Width() > widths[c] {
+				widths[c] = cell.Width()
+			}
+		}
+	}
+	for c, column := range this.Columns {
+		if column.Align != AlignDecimal {
+			continue
+		}
+		if column.Width == 0 {
+			widths[c] = points[c] + after[c]
+		} else {
+			//keep the decimal points where they would be if the column were right aligned
+			if column.Width > points[c]+after[c] {
+				points[c] += column.Width - points[c] - after[c]
+			}
+		}
+	}
+	return layouts, widths, points
+}
+
+//Size returns the width and height the table will occupy
+func (this *Table) Size() (w, h float32) {
+	_, widths, _ := this.cells()
+	for _, cw := range widths {
+		w += cw
+	}
+	if len(widths) > 1 {
+		w += this.Gap * float32(len(widths)-1)
+	}
+	return w, float32(len(this.rows)) * this.font.cellHeight
+}
+
+func (this *Table) Draw(x, y float32) {
+	layouts, widths, points := this.cells()
+	rowY := y
+	for _, row := range layouts {
+		cellX := x
+		for c, cell := range row {
+			if cell != nil {
+				offset := alignOffset(this.Columns[c].Align, cell.Width(), widths[c])
+				if this.Columns[c].Align == AlignDecimal {
+					offset = points[c] - cell.decimalOffset()
+				}
+				cell.MoveTo(cellX+offset, rowY).Draw()
+			}
+			cellX += widths[c] + this.Gap
+		}
+		rowY -= this.font.cellHeight
+	}
+}
+
+//alignOffset is how far from the left of a space of the given width text must start to be aligned within it
+func alignOffset(align Align, textWidth, width float32) float32 {
+	switch align {
+	case AlignCenter:
+		return (width - textWidth) / 2
+	case AlignRight:
+		return width - textWidth
+	}
+	return 0
+}
@@ -0,0 +1,91 @@
+package gltext
+
+//TabStop is a position, measured from the start of the line, that a tab advances to.
+//Text after the tab is placed according to Align: starting at the stop, centred on it, ending at it, or with its decimal point on it.
+type TabStop struct {
+	Position float32
+	Align    Align
+}
+
+//TabWidth is the spacing of the default tab stops, in spaces, used past the last explicit stop
+var TabWidth = 4
+
+type pendingTab struct {
+	stop  TabStop
+	lineX float32
+	tab   int //glyph index of the tab
+	start int //first glyph after the tab, or -1 when no tab is pending
+}
+
+func (this *Font) tabWidth() float32 {
+	if index, err := this.glyphIndex(' '); err == nil {
+		return this.atlas.offsets[index] * float32(TabWidth)
+	}
+	return this.cellHeight * float32(TabWidth) / 2
+}
+
+//tabStop finds the first stop after pos, falling back to multiples of the default tab width
+func (this Style) tabStop(pos, defaultWidth float32) TabStop {
+	for _, stop := range this.TabStops {
+		if stop.Position > pos {
+			return stop
+		}
+	}
+	if defaultWidth <= 0 {
+		return TabStop{Position: pos}
+	}
+	n := float32(int(pos/defaultWidth)) + 1
+	return TabStop{Position: n * defaultWidth}
+}
+
+//alignTab moves the text that followed a non-left tab now that its width is known, widening the tab to suit, and returns the new pen position
+func (this *Layout) alignTab(tab pendingTab, penX float32) float32 {
+	if tab.start < 0 || tab.stop.Align == AlignLeft {
+		return penX
+	}
+	tabGlyph := &this.glyphs[tab.tab]
+	segmentStart := tabGlyph.x + tabGlyph.advance
+	width := penX - segmentStart
+	before := width
+	if tab.stop.Align == AlignDecimal {
+		for i := tab.start; i < len(this.glyphs); i++ {
+			if this.glyphs[i].r == '.' {
+				before = this.glyphs[i].x - segmentStart
+				break
+			}
+		}
+	}
+
+	target := tab.lineX + tab.stop.Position
+	switch tab.stop.Align {
+	case AlignRight:
+		target -= width
+	case AlignCenter:
+		target -= width / 2
+	case AlignDecimal:
+		target -= before
+	}
+	//never move text backwards over what precedes the tab
+	if target < tabGlyph.x {
+		target = tabGlyph.x
+	}
+	shift := target - segmentStart
+	tabGlyph.advance += shift
+	for i := tab.start; i < len(this.glyphs); i++ {
+		this.glyphs[i].x += shift
+	}
+	return penX + shift
+}
+
+//decimalOffset is the distance from the layout's origin to its first '.', or its width if it has none
+func (this *Layout) decimalOffset() float32 {
+	for _, g := range this.glyphs {
+		if g.r == '.' {
+			return g.x - this.x
+		}
+		if isLineBreak(g.r) {
+			break
+		}
+	}
+	return this.lines[0].width
+}
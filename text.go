@@ -0,0 +1,156 @@
+package gltext
+
+import (
+	"time"
+)
+
+//Text is a string laid out once and drawn many times, optionally fading in and out
+type Text struct {
+	Layout *Layout
+	Fade   *Fade
+}
+
+func NewText(font *Font, x, y float32, style Style, s string) *Text {
+	return &Text{Layout: font.LayoutStyle(x, y, style, s)}
+}
+
+//Update advances the text's fade, if it has one
+func (this *Text) Update(dt time.Duration) {
+	if this.Fade != nil {
+		this.Fade.Update(dt)
+	}
+}
+
+//Done reports whether the text has faded out and can be discarded
+func (this *Text) Done() bool {
+	return this.Fade != nil && this.Fade.Done()
+}
+
+func (this *Text) Draw() {
+	if this.Fade == nil {
+		this.Layout.Draw()
+		return
+	}
+	if alpha := this.Fade.Alpha(); alpha > 0 {
+		this.Layout.DrawAlpha(alpha)
+	}
+}
+
+//SetAlpha sets the opacity of the text apart from its color and its fade, which multiply it
+func (this *Text) SetAlpha(alpha float32) {
+	this.Layout.SetAlpha(alpha)
+}
+
+//alpha is the text's opacity, its fade's times its layout's
+func (this *Text) alpha() float32 {
+	alpha := this.Layout.alpha
+	if this.Fade != nil {
+		alpha *= this.Fade.Alpha()
+	}
+	return alpha
+}
+
+//DrawTexts draws many texts at once. the glyphs of texts sharing a font go up in one batch, and on the GlyphStorage
+//path every pass over them is drawn with one indirect multi-draw per atlas page rather than a call per text.
+//backgrounds are drawn under the glyphs of all the texts, and decorations over them. texts with a halo are drawn
+//one at a time, since the halo's width is not part of the DrawParams.
+func DrawTexts(texts []*Text) {
+	var fonts []*Font
+	byFont := make(map[*Font][]*Text)
+	for _, t := range texts {
+		if t.alpha() <= 0 {
+			continue
+		}
+		font := t.Layout.font
+		if _, ok := byFont[font]; !ok {
+			fonts = append(fonts, font)
+		}
+		byFont[font] = append(byFont[font], t)
+	}
+	for _, font := range fonts {
+		font.drawTexts(byFont[font])
+	}
+}
+
+func (this *Font) drawTexts(texts []*Text) {
+	layouts, styles, alphas := make([]*Layout, len(texts)), make([]Style, len(texts)), make([]float32, len(texts))
+	for i, t := range texts {
+		layouts[i], styles[i], alphas[i] = t.Layout, t.Layout.style, t.alpha()
+		if alphas[i] != 1 {
+			styles[i] = styles[i].withAlpha(alphas[i])
+		}
+	}
+	this.drawLayouts(layouts, styles, alphas)
+}
+
+//Easing maps linear progress in [0, 1] to eased progress
+type Easing func(t float32) float32
+
+func Linear(t float32) float32 {
+	return t
+}
+
+func EaseIn(t float32) float32 {
+	return t * t
+}
+
+func EaseOut(t float32) float32 {
+	return t * (2 - t)
+}
+
+func EaseInOut(t float32) float32 {
+	return t * t * (3 - 2*t)
+}
+
+//Fade is an opacity envelope: it rises over In, stays opaque for Hold and falls over Out.
+//A zero Hold with a zero Out holds forever.
+type Fade struct {
+	In, Hold, Out time.Duration
+	Easing        Easing
+
+	elapsed time.Duration
+}
+
+func NewFade(in, hold, out time.Duration) *Fade {
+	return &Fade{In: in, Hold: hold, Out: out, Easing: EaseInOut}
+}
+
+func (this *Fade) Update(dt time.Duration) {
+	this.elapsed += dt
+}
+
+func (this *Fade) Reset() {
+	this.elapsed = 0
+}
+
+//FadeOut skips to the start of the fade out, for dismissing a message early
+func (this *Fade) FadeOut() {
+	if this.elapsed < this.In+this.Hold {
+		this.elapsed = this.In + this.Hold
+	}
+}
+
+func (this *Fade) Done() bool {
+	if this.Hold == 0 && this.Out == 0 {
+		return false
+	}
+	return this.elapsed >= this.In+this.Hold+this.Out
+}
+
+func (this *Fade) Alpha() float32 {
+	ease := this.Easing
+	if ease == nil {
+		ease = Linear
+	}
+	switch {
+	case this.elapsed < this.In:
+		return ease(float32(this.elapsed) / float32(this.In))
+	case this.Hold == 0 && this.Out == 0:
+		return 1
+	case this.elapsed < this.In+this.Hold:
+		return 1
+	case this.elapsed < this.In+this.Hold+this.Out:
+		return 1 - ease(float32(this.elapsed-this.In-this.Hold)/float32(this.Out))
+	}
+	return 0
+}
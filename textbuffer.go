@@ -0,0 +1,245 @@
+package gltext
+
+//TextBuffer is editable text held in a gap buffer, so typing in one place costs no more than the runes typed, with
+//undo and redo. it tracks which lines edits touch, so that a BufferLayout of it lays out only those again.
+//it is the model behind TextInput.
+type TextBuffer struct {
+	runes       []rune
+	gap, gapEnd int //the unused runes[gap:gapEnd] sit at the last edit
+	undo, redo  []bufferEdit
+	lineStarts  []int //the index of the first rune of each line
+	damage      lineDamage
+}
+
+//bufferEdit is one undoable step: removed was replaced by inserted at rune index at
+type bufferEdit struct {
+	at       int
+	removed  []rune
+	inserted []rune
+}
+
+//lineDamage is the lines [first, end) edited since a layout last caught up, which were [first, end-delta) before
+type lineDamage struct {
+	first, end, delta int
+	any               bool
+}
+
+func NewTextBuffer(s string) *TextBuffer {
+	this := &TextBuffer{runes: []rune(s)}
+	this.gap, this.gapEnd = len(this.runes), len(this.runes)
+	this.lineStarts = this.scanLines(0, this.Len()+1)
+	this.damage = lineDamage{0, len(this.lineStarts), 0, true}
+	return this
+}
+
+func (this *TextBuffer) Len() int {
+	return len(this.runes) - (this.gapEnd - this.gap)
+}
+
+func (this *TextBuffer) RuneAt(i int) rune {
+	if i >= this.gap {
+		i += this.gapEnd - this.gap
+	}
+	return this.runes[i]
+}
+
+//Runes returns the whole text, which is only valid until the next edit
+func (this *TextBuffer) Runes() []rune {
+	this.moveGap(this.Len())
+	return this.runes[:this.gap]
+}
+
+func (this *TextBuffer) String() string {
+	return string(this.Runes())
+}
+
+//Slice returns a copy of the runes [start, end)
+func (this *TextBuffer) Slice(start, end int) []rune {
+	runes := make([]rune, 0, end-start)
+	for i := start; i < end; i++ {
+		runes = append(runes, this.RuneAt(i))
+	}
+	return runes
+}
+
+//moveGap moves the gap to just before rune index at
+func (this *TextBuffer) moveGap(at int) {
+	size := this.gapEnd - this.gap
+	if at < this.gap {
+		copy(this.runes[at+size:this.gapEnd], this.runes[at:this.gap])
+	} else {
+		copy(this.runes[this.gap:at], this.runes[this.gapEnd:at+size])
+	}
+	this.gap, this.gapEnd = at, at+size
+}
+
+//splice replaces the runes [start, end) with inserted and returns those it removed
+func (this *TextBuffer) splice(start, end int, inserted []rune) []rune {
+	removed := this.Slice(start, end)
+	this.moveGap(start)
+	this.gapEnd += end - start
+	if need := len(inserted) - (this.gapEnd - this.gap); need > 0 {
+		//grow the gap to twice what is needed, so that runs of typing don't grow it every time
+		grown := make([]rune, len(this.runes)+2*need+16)
+		copy(grown, this.runes[:this.gap])
+		tail := len(this.runes) - this.gapEnd
+		copy(grown[len(grown)-tail:], this.runes[this.gapEnd:])
+		this.runes, this.gapEnd = grown, len(grown)-tail
+	}
+	copy(this.runes[this.gap:], inserted)
+	this.gap += len(inserted)
+	this.updateLines(start, len(removed), len(inserted))
+	return removed
+}
+
+//Replace replaces the runes [start, end) with s as one undoable step, and returns the index just after what it
+//inserted. typing, one insertion straight after another, is undone a word at a time.
+func (this *TextBuffer) Replace(start, end int, s string) int {
+	inserted := []rune(s)
+	if start == end && len(inserted) == 0 {
+		return start
+	}
+	removed := this.splice(start, end, inserted)
+	this.redo = this.redo[:0]
+	if n := len(this.undo); n > 0 && start == end && this.continuesTyping(&this.undo[n-1], start, inserted) {
+		this.undo[n-1].inserted = append(this.undo[n-1].inserted, inserted...)
+	} else {
+		this.undo = append(this.undo, bufferEdit{start, removed, inserted})
+	}
+	return start + len(inserted)
+}
+
+func (this *TextBuffer) continuesTyping(last *bufferEdit, at int, inserted []rune) bool {
+	if len(last.removed) > 0 || len(last.inserted) == 0 || last.at+len(last.inserted) != at {
+		return false
+	}
+	//a space ends the word being typed, so what follows is undone separately
+	return last.inserted[len(last.inserted)-1] != ' ' || inserted[0] == ' '
+}
+
+func (this *TextBuffer) Insert(at int, s string) int {
+	return this.Replace(at, at, s)
+}
+
+func (this *TextBuffer) Delete(start, end int) {
+	this.Replace(start, end, "")
+}
+
+//Undo reverts the last step, returning where the caret belongs after it, or false if there is nothing to undo
+func (this *TextBuffer) Undo() (int, bool) {
+	n := len(this.undo)
+	if n == 0 {
+		return 0, false
+	}
+	e := this.undo[n-1]
+	this.undo = this.undo[:n-1]
+	this.splice(e.at, e.at+len(e.inserted), e.removed)
+	this.redo = append(this.redo, e)
+	return e.at + len(e.removed), true
+}
+
+//Redo makes the last step undone again, returning where the caret belongs after it, or false if there is nothing to redo
+func (this *TextBuffer) Redo() (int, bool) {
+	n := len(this.redo)
+	if n == 0 {
+		return 0, false
+	}
+	e := this.redo[n-1]
+	this.redo = this.redo[:n-1]
+	this.splice(e.at, e.at+len(e.removed), e.inserted)
+	this.undo = append(this.undo, e)
+	return e.at + len(e.inserted), true
+}
+
+//ClearUndo forgets every step, as when the text is replaced outright
+func (this *TextBuffer) ClearUndo() {
+	this.undo, this.redo = this.undo[:0], this.redo[:0]
+}
+
+func (this *TextBuffer) LineCount() int {
+	return len(this.lineStarts)
+}
+
+//Line returns the runes [start, end) of line i, without the line break that ends it
+func (this *TextBuffer) Line(i int) (start, end int) {
+	start, end = this.lineStarts[i], this.Len()
+	if i+1 < len(this.lineStarts) {
+		end = this.lineStarts[i+1] - 1
+		if end > start && this.RuneAt(end) == '\n' && this.RuneAt(end-1) == '\r' {
+			end--
+		}
+	}
+	return start, end
+}
+
+//LineOf returns the line that rune index i is on
+func (this *TextBuffer) LineOf(i int) int {
+	line := 0
+	for line+1 < len(this.lineStarts) && this.lineStarts[line+1] <= i {
+		line++
+	}
+	return line
+}
+
+//scanLines returns the starts of the lines beginning at from, up to but not including any that start at to or later
+func (this *TextBuffer) scanLines(from, to int) []int {
+	starts := []int{from}
+	for i := from; i < to-1; i++ {
+		if this.breaksAt(i) {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+//breaksAt reports whether rune i ends a line, as breakAt does for layout
+func (this *TextBuffer) breaksAt(i int) bool {
+	switch this.RuneAt(i) {
+	case '\r':
+		return i+1 >= this.Len() || this.RuneAt(i+1) != '\n'
+	case '\n', LineSeparator, ParagraphSeparator:
+		return true
+	}
+	return false
+}
+
+//updateLines finds the lines again around an edit at start that replaced removed runes with inserted ones
+func (this *TextBuffer) updateLines(start, removed, inserted int) {
+	old := this.lineStarts
+	first := maxInt(this.LineOf(start)-1, 0) //the line before, in case the edit joined a "\r\n"
+	last := this.LineOf(start + removed)
+	tail := make([]int, 0, len(old)-last-1)
+	for _, s := range old[last+1:] {
+		tail = append(tail, s+inserted-removed)
+	}
+	to := this.Len() + 1
+	if len(tail) > 0 {
+		to = tail[0]
+	}
+	scanned := this.scanLines(old[first], to)
+	this.lineStarts = append(append(old[:first:first], scanned...), tail...)
+	this.damageLines(first, last+1, first+len(scanned))
+}
+
+//damageLines adds lines [first, oldEnd) having become [first, newEnd) to the damage
+func (this *TextBuffer) damageLines(first, oldEnd, newEnd int) {
+	delta := newEnd - oldEnd
+	d := &this.damage
+	if !d.any {
+		*d = lineDamage{first, newEnd, delta, true}
+		return
+	}
+	//damage past the edit moves with the lines after it, and damage within it is covered by the edit's own
+	end := newEnd
+	if d.end > oldEnd {
+		end = d.end + delta
+	}
+	*d = lineDamage{minInt(d.first, first), end, d.delta + delta, true}
+}
+
+//takeDamage returns the lines edited since it was last called
+func (this *TextBuffer) takeDamage() lineDamage {
+	d := this.damage
+	this.damage = lineDamage{}
+	return d
+}
@@ -0,0 +1,69 @@
+package gltext
+
+import (
+	"math/rand"
+	"testing"
+)
+
+//relayout brings lines up to date with b's damage as BufferLayout.Update does, a line at a time
+func relayout(b *TextBuffer, lines []string) []string {
+	d := b.takeDamage()
+	if !d.any {
+		return lines
+	}
+	fresh := make([]string, 0, d.end-d.first)
+	for i := d.first; i < d.end; i++ {
+		start, end := b.Line(i)
+		fresh = append(fresh, string(b.Slice(start, end)))
+	}
+	after := lines[minInt(d.end-d.delta, len(lines)):]
+	return append(append(lines[:d.first:d.first], fresh...), after...)
+}
+
+func TestTextBufferDamage(t *testing.T) {
+	b := NewTextBuffer("x\ny\nz")
+	lines := relayout(b, nil)
+	b.Replace(4, 5, "q")
+	b.Replace(1, 5, "")
+	lines = relayout(b, lines)
+	if len(lines) != 1 || lines[0] != "x" {
+		t.Fatalf("got %q, want [\"x\"]", lines)
+	}
+}
+
+func TestTextBufferRandomEdits(t *testing.T) {
+	pieces := []string{"", "a", "bc", "\n", "\r", "\r\n", "d\ne", "\n\n", string(LineSeparator), "f\rg"}
+	r := rand.New(rand.NewSource(1))
+	for run := 0; run < 2000; run++ {
+		b := NewTextBuffer(pieces[r.Intn(len(pieces))])
+		lines := relayout(b, nil)
+		for batch := 0; batch < 10; batch++ {
+			for edits := r.Intn(4) + 1; edits > 0; edits-- {
+				switch r.Intn(5) {
+				case 0:
+					b.Undo()
+				case 1:
+					b.Redo()
+				default:
+					start := r.Intn(b.Len() + 1)
+					end := start + r.Intn(b.Len()-start+1)
+					b.Replace(start, end, pieces[r.Intn(len(pieces))])
+				}
+			}
+			lines = relayout(b, lines)
+			if len(lines) != b.LineCount() {
+				t.Fatalf("run %d: %d lines laid out, buffer %q has %d", run, len(lines), b.String(), b.LineCount())
+			}
+			want := NewTextBuffer(b.String())
+			for i := range lines {
+				start, end := b.Line(i)
+				if got := string(b.Slice(start, end)); lines[i] != got {
+					t.Fatalf("run %d: line %d laid out as %q, buffer %q has %q", run, i, lines[i], b.String(), got)
+				}
+				if ws, we := want.Line(i); string(want.Slice(ws, we)) != lines[i] {
+					t.Fatalf("run %d: line %d of %q tracked as %q, scanned afresh as %q", run, i, b.String(), lines[i], string(want.Slice(ws, we)))
+				}
+			}
+		}
+	}
+}
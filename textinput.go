@@ -0,0 +1,442 @@
+package gltext
+
+import (
+	"sort"
+	"time"
+)
+
+//PasswordMask is the conventional TextInput.Mask for password fields. fonts baked with only ASCII lack it, so they
+//need it baked in too, or '*' used instead.
+const PasswordMask = '\u2022'
+
+//TextInput is a single line editable text field drawn with a Font.
+//Feed it key events from your windowing library and call Draw each frame.
+type TextInput struct {
+	X, Y, Width    float32
+	Style          Style
+	SelectionColor Vector4
+	CaretColor     Vector4
+	Blink          CaretBlink //advanced by Update
+	//Mask, when not zero, is drawn once for each grapheme cluster in place of the text. the caret and selection still
+	//index the real text, and move and delete a cluster at a time.
+	Mask rune
+	//AccessibleName is what screen readers announce the field as, such as the label drawn beside it
+	AccessibleName string
+
+	font   *Font
+	buffer *TextBuffer //the text, whose edits can be undone
+	caret  int
+	anchor int //the other end of the selection; equal to caret when nothing is selected
+	scroll float32
+
+	//an input method's in-progress composition, shown at the caret but not yet part of text
+	composition       []rune
+	compositionCursor int
+}
+
+func NewTextInput(font *Font, x, y, width float32) *TextInput {
+	return &TextInput{
+		X:              x,
+		Y:              y,
+		Width:          width,
+		Style:          font.style,
+		SelectionColor: Vector4{0.2, 0.4, 0.9, 0.5},
+		CaretColor:     Vector4{1, 1, 1, 1},
+		Blink:          CaretBlink{Period: DefaultCaretBlink},
+		font:           font,
+		buffer:         NewTextBuffer(""),
+	}
+}
+
+func (this *TextInput) Text() string {
+	return this.buffer.String()
+}
+
+//SetText replaces the text outright, which can't be undone
+func (this *TextInput) SetText(s string) {
+	this.buffer.Replace(0, this.buffer.Len(), s)
+	this.buffer.ClearUndo()
+	this.caret = this.buffer.Len()
+	this.anchor = this.caret
+	this.scrollToCaret()
+}
+
+//Buffer returns the text being edited
+func (this *TextInput) Buffer() *TextBuffer {
+	return this.buffer
+}
+
+//runes returns the text, which is only valid until the next edit
+func (this *TextInput) runes() []rune {
+	return this.buffer.Runes()
+}
+
+func (this *TextInput) Caret() int {
+	return this.caret
+}
+
+//Selection returns the selected rune range [start, end); start == end when nothing is selected
+func (this *TextInput) Selection() (start, end int) {
+	if this.anchor < this.caret {
+		return this.anchor, this.caret
+	}
+	return this.caret, this.anchor
+}
+
+func (this *TextInput) HasSelection() bool {
+	return this.anchor != this.caret
+}
+
+func (this *TextInput) SelectAll() {
+	this.anchor = 0
+	this.caret = this.buffer.Len()
+	this.scrollToCaret()
+}
+
+func (this *TextInput) SelectedText() string {
+	start, end := this.Selection()
+	return string(this.buffer.Slice(start, end))
+}
+
+//InsertRune replaces the selection, if any, with r
+func (this *TextInput) InsertRune(r rune) {
+	this.InsertString(string(r))
+}
+
+func (this *TextInput) InsertString(s string) {
+	start, end := this.Selection()
+	this.caret = this.buffer.Replace(start, end, s)
+	this.anchor = this.caret
+	this.scrollToCaret()
+}
+
+//Backspace deletes the selection, or the rune before the caret
+func (this *TextInput) Backspace() {
+	if !this.deleteSelection() && this.caret > 0 {
+		before := this.stepBack(this.caret)
+		this.buffer.Delete(before, this.caret)
+		this.caret = before
+		this.anchor = this.caret
+	}
+	this.scrollToCaret()
+}
+
+//Delete deletes the selection, or the rune after the caret
+func (this *TextInput) Delete() {
+	if !this.deleteSelection() && this.caret < this.buffer.Len() {
+		this.buffer.Delete(this.caret, this.stepForward(this.caret))
+	}
+	this.scrollToCaret()
+}
+
+//Undo reverts the last edit, typing being undone a word at a time. it returns false if there was nothing to undo.
+func (this *TextInput) Undo() bool {
+	caret, ok := this.buffer.Undo()
+	if ok {
+		this.caret, this.anchor = caret, caret
+		this.scrollToCaret()
+	}
+	return ok
+}
+
+//Redo makes the last edit undone again, returning false if there was nothing to redo
+func (this *TextInput) Redo() bool {
+	caret, ok := this.buffer.Redo()
+	if ok {
+		this.caret, this.anchor = caret, caret
+		this.scrollToCaret()
+	}
+	return ok
+}
+
+//stepBack returns the index of the rune before i, or with a mask the start of the grapheme cluster before i
+func (this *TextInput) stepBack(i int) int {
+	if this.Mask == 0 {
+		return i - 1
+	}
+	starts := graphemeStarts(this.runes())
+	if n := sort.SearchInts(starts, i); n > 0 {
+		return starts[n-1]
+	}
+	return 0
+}
+
+//stepForward returns the index of the rune after i, or with a mask the end of the grapheme cluster after i
+func (this *TextInput) stepForward(i int) int {
+	if this.Mask == 0 {
+		return i + 1
+	}
+	starts := graphemeStarts(this.runes())
+	if n := sort.SearchInts(starts, i+1); n < len(starts) {
+		return starts[n]
+	}
+	return this.buffer.Len()
+}
+
+func (this *TextInput) deleteSelection() bool {
+	if !this.HasSelection() {
+		return false
+	}
+	start, end := this.Selection()
+	this.buffer.Delete(start, end)
+	this.caret = start
+	this.anchor = start
+	return true
+}
+
+//the Move methods extend the selection when selecting is true, otherwise they clear it
+
+func (this *TextInput) MoveLeft(selecting bool) {
+	if this.HasSelection() && !selecting {
+		start, _ := this.Selection()
+		this.moveTo(start, false)
+		return
+	}
+	this.moveTo(this.stepBack(this.caret), selecting)
+}
+
+func (this *TextInput) MoveRight(selecting bool) {
+	if this.HasSelection() && !selecting {
+		_, end := this.Selection()
+		this.moveTo(end, false)
+		return
+	}
+	this.moveTo(this.stepForward(this.caret), selecting)
+}
+
+//MoveWord moves to the start of the previous word when direction is negative, otherwise to the end of the next word.
+//with a mask it moves to the start or end of the text, so as not to give away where the words are.
+func (this *TextInput) MoveWord(direction int, selecting bool) {
+	if this.Mask != 0 {
+		if direction < 0 {
+			this.Home(selecting)
+		} else {
+			this.End(selecting)
+		}
+		return
+	}
+	text := this.runes()
+	bounds := wordBoundaries(text)
+	i := 0
+	if direction < 0 {
+		for k := len(bounds) - 2; k >= 0; k-- {
+			if bounds[k] < this.caret && isWord(text[bounds[k]]) {
+				i = bounds[k]
+				break
+			}
+		}
+	} else {
+		i = len(text)
+		for k := 1; k < len(bounds); k++ {
+			if bounds[k] > this.caret && isWord(text[bounds[k-1]]) {
+				i = bounds[k]
+				break
+			}
+		}
+	}
+	this.moveTo(i, selecting)
+}
+
+//SelectWord selects the word, run of spaces or punctuation at the position nearest x; use it for double clicks
+func (this *TextInput) SelectWord(x float32) {
+	this.HitTest(x, false)
+	if this.Mask != 0 {
+		this.SelectAll()
+		return
+	}
+	start, end := wordAt(this.runes(), this.caret)
+	this.anchor = start
+	this.moveTo(end, true)
+}
+
+func (this *TextInput) Home(selecting bool) {
+	this.moveTo(0, selecting)
+}
+
+func (this *TextInput) End(selecting bool) {
+	this.moveTo(this.buffer.Len(), selecting)
+}
+
+func (this *TextInput) moveTo(index int, selecting bool) {
+	if index < 0 {
+		index = 0
+	}
+	if index > this.buffer.Len() {
+		index = this.buffer.Len()
+	}
+	this.caret = index
+	if !selecting {
+		this.anchor = index
+	}
+	this.scrollToCaret()
+}
+
+//HitTest moves the caret to the position nearest x; use it for mouse clicks inside the field
+func (this *TextInput) HitTest(x float32, selecting bool) {
+	for i, g := range this.layout().glyphs {
+		if x < g.x+g.advance/2 {
+			this.moveTo(this.textIndex(i), selecting)
+			return
+		}
+	}
+	this.moveTo(this.buffer.Len(), selecting)
+}
+
+//SetComposition shows an input method's pre-edit text at the caret, with the IME's cursor at the given rune offset within it.
+//Call it again whenever the IME updates, and InsertString followed by ClearComposition when it commits.
+func (this *TextInput) SetComposition(s string, cursor int) {
+	this.composition = []rune(s)
+	if cursor < 0 || cursor > len(this.composition) {
+		cursor = len(this.composition)
+	}
+	this.compositionCursor = cursor
+	this.scrollToCaret()
+}
+
+func (this *TextInput) ClearComposition() {
+	this.composition = nil
+	this.compositionCursor = 0
+	this.scrollToCaret()
+}
+
+func (this *TextInput) Composing() bool {
+	return len(this.composition) > 0
+}
+
+//CompositionRect returns the area covered by the composition, or the caret when not composing.
+//Position the IME candidate window just below it.
+func (this *TextInput) CompositionRect() Rect {
+	layout := this.layout()
+	x, y, height := layout.CaretPos(this.layoutIndex(this.caret))
+	x2, _, _ := layout.CaretPos(this.layoutIndex(this.caret + len(this.composition)))
+	return Rect{x, y, x2 - x, height}
+}
+
+//displayText is the text with any composition spliced in at the caret, or the mask in its place, and the caret
+//position within it
+func (this *TextInput) displayText() (string, int) {
+	runes, caret := this.displayRunes()
+	if this.Mask == 0 {
+		return string(runes), caret
+	}
+	starts := graphemeStarts(runes)
+	mask := make([]rune, len(starts))
+	for i := range mask {
+		mask[i] = this.Mask
+	}
+	return string(mask), sort.SearchInts(starts, caret)
+}
+
+//displayRunes is the text with any composition spliced in at the caret, and the caret position within it
+func (this *TextInput) displayRunes() ([]rune, int) {
+	text := this.runes()
+	if !this.Composing() {
+		return text, this.caret
+	}
+	runes := make([]rune, 0, len(text)+len(this.composition))
+	runes = append(runes, text[:this.caret]...)
+	runes = append(runes, this.composition...)
+	runes = append(runes, text[this.caret:]...)
+	return runes, this.caret + this.compositionCursor
+}
+
+//layoutIndex returns the glyph of the layout at rune index i of the displayed runes, which is the index of the grapheme
+//cluster with a mask
+func (this *TextInput) layoutIndex(i int) int {
+	if this.Mask == 0 {
+		return i
+	}
+	runes, _ := this.displayRunes()
+	return sort.SearchInts(graphemeStarts(runes), i)
+}
+
+//textIndex is the inverse of layoutIndex
+func (this *TextInput) textIndex(glyph int) int {
+	if this.Mask == 0 {
+		return glyph
+	}
+	runes, _ := this.displayRunes()
+	if starts := graphemeStarts(runes); glyph < len(starts) {
+		return starts[glyph]
+	}
+	return len(runes)
+}
+
+func (this *TextInput) layout() *Layout {
+	s, _ := this.displayText()
+	return this.font.LayoutStyle(this.X-this.scroll, this.Y, this.Style, s)
+}
+
+//Update advances the caret's blink
+func (this *TextInput) Update(dt time.Duration) {
+	this.Blink.Update(dt)
+}
+
+//scrollToCaret scrolls horizontally just enough to keep the caret inside the field, and shows it, since it has moved
+func (this *TextInput) scrollToCaret() {
+	this.Blink.Reset()
+	s, caret := this.displayText()
+	caretX, _, _ := this.font.LayoutStyle(0, 0, this.Style, s).CaretPos(caret)
+	if caretX-this.scroll > this.Width {
+		this.scroll = caretX - this.Width
+	}
+	if caretX < this.scroll {
+		this.scroll = caretX
+	}
+}
+
+func (this *TextInput) Draw() {
+	layout := this.layout()
+	minX, maxX := this.X, this.X+this.Width
+
+	if this.HasSelection() && !this.Composing() {
+		start, end := this.Selection()
+		for _, r := range layout.SelectionRects(this.layoutIndex(start), this.layoutIndex(end)) {
+			x1, x2 := r.X, r.X+r.W
+			if x1 < minX {
+				x1 = minX
+			}
+			if x2 > maxX {
+				x2 = maxX
+			}
+			if x2 > x1 {
+				this.font.fillRect(x1, r.Y, x2-x1, r.H, this.SelectionColor)
+			}
+		}
+	}
+
+	layout.clipX(minX, maxX)
+	layout.Draw()
+
+	if this.Composing() {
+		this.drawCompositionUnderline(minX, maxX)
+	}
+
+	if this.Blink.Visible() {
+		_, caret := this.displayText()
+		layout.DrawCaret(caret, this.CaretColor)
+	}
+}
+
+//drawCompositionUnderline draws the conventional dashed line under pre-edit text
+func (this *TextInput) drawCompositionUnderline(minX, maxX float32) {
+	r := this.CompositionRect()
+	thickness := 2 / this.font.source.height
+	dash := 8 / this.font.source.width
+	y := r.Y - this.font.baseline - 2*thickness
+	for x := r.X; x < r.X+r.W; x += 2 * dash {
+		x1, x2 := x, x+dash
+		if x2 > r.X+r.W {
+			x2 = r.X + r.W
+		}
+		if x1 < minX {
+			x1 = minX
+		}
+		if x2 > maxX {
+			x2 = maxX
+		}
+		if x2 > x1 {
+			this.font.fillRect(x1, y, x2-x1, thickness, this.Style.Color)
+		}
+	}
+}
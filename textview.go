@@ -0,0 +1,68 @@
+package gltext
+
+//TextView shows a block of text inside a fixed rectangle, scrolled vertically and clipped to its edges
+type TextView struct {
+	X, Y, Width, Height float32
+	Style               Style
+	AccessibleName      string //what screen readers announce the view as
+
+	font    *Font
+	content *Layout
+	scroll  float32 //distance the content has been scrolled up, in draw units
+}
+
+func NewTextView(font *Font, x, y, width, height float32) *TextView {
+	this := &TextView{X: x, Y: y, Width: width, Height: height, Style: font.style, font: font}
+	this.SetText("")
+	return this
+}
+
+func (this *TextView) SetText(s string) {
+	this.content = this.font.LayoutStyle(this.X, this.Y, this.Style, s)
+	this.ScrollTo(this.scroll)
+}
+
+func (this *TextView) Content() *Layout {
+	return this.content
+}
+
+func (this *TextView) ScrollOffset() float32 {
+	return this.scroll
+}
+
+//MaxScroll is the offset at which the last line of content reaches the bottom of the view
+func (this *TextView) MaxScroll() float32 {
+	if max := this.content.Height() - this.Height; max > 0 {
+		return max
+	}
+	return 0
+}
+
+func (this *TextView) ScrollTo(offset float32) {
+	if offset > this.MaxScroll() {
+		offset = this.MaxScroll()
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	this.scroll = offset
+}
+
+func (this *TextView) ScrollBy(delta float32) {
+	this.ScrollTo(this.scroll + delta)
+}
+
+//ScrollLines scrolls by whole lines; negative values scroll back towards the top
+func (this *TextView) ScrollLines(lines int) {
+	this.ScrollBy(float32(lines) * this.font.cellHeight)
+}
+
+func (this *TextView) Rect() Rect {
+	return Rect{this.X, this.Y, this.Width, this.Height}
+}
+
+func (this *TextView) Draw() {
+	this.font.withClip(this.Rect(), func() {
+		this.content.MoveTo(this.X, this.Y+this.scroll).Draw()
+	})
+}
@@ -0,0 +1,142 @@
+package gltext
+
+import (
+	"math"
+	"time"
+)
+
+//Toasts shows pushed messages stacked in a corner of the screen, each on its Style's background. a message fades in,
+//stays for Hold and fades out, and those after it slide into the space it leaves.
+type Toasts struct {
+	Corner        Corner
+	Margin        float32 //distance from the screen edges to the backgrounds, in draw units
+	Spacing       float32 //between the backgrounds of successive messages
+	MaxWidth      float32 //messages are wrapped to fit; zero for no wrapping
+	Style         Style
+	In, Hold, Out time.Duration
+	Slide         time.Duration //roughly how long the stack takes to close up
+	Max           int           //most messages shown at once; pushing more fades the oldest out early
+
+	font   *Font
+	toasts []*toast
+}
+
+type toast struct {
+	text   *Text
+	offset float32 //how far from the corner the toast's background starts, moving towards where it belongs
+}
+
+func NewToasts(font *Font) *Toasts {
+	style := font.style
+	style.Background = Background{Color: Vector4{0, 0, 0, 0.7}, Padding: 0.015, Radius: 0.01}
+	return &Toasts{
+		Corner:  TopRight,
+		Margin:  0.02,
+		Spacing: 0.01,
+		Style:   style,
+		In:      150 * time.Millisecond,
+		Hold:    3 * time.Second,
+		Out:     500 * time.Millisecond,
+		Slide:   150 * time.Millisecond,
+		Max:     5,
+		font:    font,
+	}
+}
+
+//Push shows a message after those already shown
+func (this *Toasts) Push(fs string, argv ...interface{}) {
+	this.PushStyle(this.Style, fs, argv...)
+}
+
+//PushStyle shows a message in its own style, such as a warning in red
+func (this *Toasts) PushStyle(style Style, fs string, argv ...interface{}) {
+	s := this.font.sprintf(fs, argv...)
+	var layout *Layout
+	if this.MaxWidth > 0 {
+		layout = this.font.LayoutWrapped(0, 0, this.MaxWidth, style, s)
+	} else {
+		layout = this.font.LayoutStyle(0, 0, style, s)
+	}
+	t := &toast{text: &Text{Layout: layout, Fade: NewFade(this.In, this.Hold, this.Out)}}
+	offsets := this.offsets()
+	t.offset = offsets[len(offsets)-1]
+	this.toasts = append(this.toasts, t)
+	//past Max, the oldest of those not yet fading out start to
+	showing := 0
+	for i := len(this.toasts) - 1; i >= 0; i-- {
+		f := this.toasts[i].text.Fade
+		if f.elapsed >= f.In+f.Hold {
+			continue
+		}
+		showing++
+		if this.Max > 0 && showing > this.Max {
+			f.FadeOut()
+		}
+	}
+}
+
+//offsets returns where each toast belongs, and after them where the next would go
+func (this *Toasts) offsets() []float32 {
+	offsets := make([]float32, 0, len(this.toasts)+1)
+	offset := float32(0)
+	for _, t := range this.toasts {
+		offsets = append(offsets, offset)
+		offset += this.height(t) + this.Spacing
+	}
+	return append(offsets, offset)
+}
+
+//height is the toast's height with its background
+func (this *Toasts) height(t *toast) float32 {
+	return t.text.Layout.Height() + 2*t.text.Layout.style.Background.Padding
+}
+
+//Update advances the fades, drops messages that have faded out and slides the rest towards their places
+func (this *Toasts) Update(dt time.Duration) {
+	kept := this.toasts[:0]
+	for _, t := range this.toasts {
+		t.text.Update(dt)
+		if !t.text.Done() {
+			kept = append(kept, t)
+		}
+	}
+	for i := len(kept); i < len(this.toasts); i++ {
+		this.toasts[i] = nil
+	}
+	this.toasts = kept
+	step := float32(1)
+	if this.Slide > 0 {
+		step = 1 - float32(math.Exp(-float64(dt)/float64(this.Slide)))
+	}
+	for i, offset := range this.offsets()[:len(this.toasts)] {
+		t := this.toasts[i]
+		t.offset += (offset - t.offset) * step
+	}
+}
+
+//Dismiss fades every message out early
+func (this *Toasts) Dismiss() {
+	for _, t := range this.toasts {
+		t.text.Fade.FadeOut()
+	}
+}
+
+func (this *Toasts) Clear() {
+	this.toasts = this.toasts[:0]
+}
+
+func (this *Toasts) Len() int {
+	return len(this.toasts)
+}
+
+func (this *Toasts) Draw() {
+	texts := make([]*Text, len(this.toasts))
+	for i, t := range this.toasts {
+		layout := t.text.Layout
+		pad := layout.style.Background.Padding
+		x, y := cornerOrigin(this.Corner, this.Margin, this.Margin+t.offset, layout.Width()+2*pad, layout.Height()+2*pad)
+		layout.MoveTo(x+pad, y-pad)
+		texts[i] = t.text
+	}
+	DrawTexts(texts)
+}
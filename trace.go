@@ -0,0 +1,51 @@
+package gltext
+
+//Phase is a stage of drawing text that trace hooks are told about
+type Phase int
+
+const (
+	PhaseLayout Phase = iota //measuring and placing glyphs, including rasterizing glyphs loaded on demand
+	PhaseUpload              //sending batches and atlas changes to the GPU
+	PhaseDraw                //issuing the draws of a flush
+)
+
+func (this Phase) String() string {
+	switch this {
+	case PhaseLayout:
+		return "layout"
+	case PhaseUpload:
+		return "upload"
+	case PhaseDraw:
+		return "draw"
+	}
+	return "unknown"
+}
+
+//TraceHooks are called at the start and end of each phase, on the goroutine doing the work, for wiring gltext into
+//tracing systems such as pprof labels. phases may nest: a layout that loads glyphs uploads them within it.
+//either hook may be nil.
+type TraceHooks struct {
+	Begin func(phase Phase)
+	End   func(phase Phase)
+}
+
+var traceHooks TraceHooks
+
+//SetTraceHooks replaces the trace hooks; the zero TraceHooks turns tracing off
+func SetTraceHooks(hooks TraceHooks) {
+	traceHooks = hooks
+}
+
+//beginTrace starts phase and returns it for endTrace, so that a phase can be traced with one deferred call
+func beginTrace(phase Phase) Phase {
+	if traceHooks.Begin != nil {
+		traceHooks.Begin(phase)
+	}
+	return phase
+}
+
+func endTrace(phase Phase) {
+	if traceHooks.End != nil {
+		traceHooks.End(phase)
+	}
+}
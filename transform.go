@@ -0,0 +1,41 @@
+package gltext
+
+import (
+	"unicode"
+)
+
+//Transform changes the case of text as it is laid out, leaving the source string and rune indices untouched
+type Transform int
+
+const (
+	NoTransform Transform = iota
+	Uppercase
+	Lowercase
+	//SmallCaps draws lowercase letters as capitals scaled to SmallCapsScale, sitting on the baseline
+	SmallCaps
+)
+
+//SmallCapsScale is the size of synthesized small capitals relative to full capitals
+var SmallCapsScale float32 = 0.75
+
+//apply returns the rune to draw for r and its scale, where zero means full size
+func (this Transform) apply(r rune) (rune, float32) {
+	switch this {
+	case Uppercase:
+		return unicode.ToUpper(r), 0
+	case Lowercase:
+		return unicode.ToLower(r), 0
+	case SmallCaps:
+		if unicode.IsLower(r) {
+			return unicode.ToUpper(r), SmallCapsScale
+		}
+	}
+	return r, 0
+}
+
+func (this layoutGlyph) drawScale() float32 {
+	if this.scale == 0 {
+		return 1
+	}
+	return this.scale
+}
@@ -0,0 +1,64 @@
+package gltext
+
+import (
+	"github.com/jimarnold/gl"
+)
+
+//vertexArray is a vertex array object or, on drivers without them such as OpenGL ES 2 and WebGL 1, the setup that
+//would have been recorded in one, replayed each time it is bound
+type vertexArray struct {
+	vao     gl.VertexArray             //zero without vertex array objects
+	setup   func() []gl.AttribLocation //binds the buffers, and describes and enables the attributes it returns
+	enabled []gl.AttribLocation        //enabled by the last bind without a vertex array object
+}
+
+//vertexArraysSupported reports whether the driver has vertex array objects, which came with OpenGL 3.0 and OpenGL ES 3.0
+func vertexArraysSupported() bool {
+	return glVersionAtLeast(3, 0)
+}
+
+func newVertexArray(setup func() []gl.AttribLocation) *vertexArray {
+	this := &vertexArray{setup: setup}
+	if vertexArraysSupported() {
+		this.vao = gl.GenVertexArray()
+		this.update()
+	}
+	return this
+}
+
+//update records the setup again, after the buffers it binds have been replaced
+func (this *vertexArray) update() {
+	if this.vao == 0 {
+		return
+	}
+	this.vao.Bind()
+	this.setup()
+	this.vao.Unbind()
+}
+
+func (this *vertexArray) Bind() {
+	if this.vao != 0 {
+		this.vao.Bind()
+		return
+	}
+	this.enabled = this.setup()
+}
+
+//Unbind leaves no attributes of the array enabled, so that they can't leak into the host renderer's draws without
+//vertex array objects
+func (this *vertexArray) Unbind() {
+	if this.vao != 0 {
+		this.vao.Unbind()
+		return
+	}
+	for _, a := range this.enabled {
+		a.DisableArray()
+	}
+	this.enabled = nil
+}
+
+func (this *vertexArray) Delete() {
+	if this.vao != 0 {
+		this.vao.Delete()
+	}
+}
@@ -0,0 +1,151 @@
+package gltext
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+//wordClass is the part of UAX #29 word segmentation a rune plays
+type wordClass int
+
+const (
+	wordOther wordClass = iota //punctuation and symbols, each a segment of its own
+	wordLetter
+	wordNumber
+	wordKatakana
+	wordIdeograph //Han and Hiragana, each a word of its own for want of a dictionary
+	wordSpace
+)
+
+func classifyWord(r rune) wordClass {
+	switch {
+	case unicode.In(r, unicode.Han, unicode.Hiragana):
+		return wordIdeograph
+	case unicode.Is(unicode.Katakana, r) || r == 0x30FC:
+		return wordKatakana
+	case unicode.IsLetter(r) || r == '_':
+		return wordLetter
+	case unicode.IsDigit(r):
+		return wordNumber
+	case r == ' ' || r == '\t' || unicode.Is(unicode.Zs, r):
+		return wordSpace
+	}
+	return wordOther
+}
+
+//isWordExtend reports whether r belongs to the rune before it whatever that is: marks, joiners and other format runes
+func isWordExtend(r rune) bool {
+	return unicode.In(r, unicode.M, unicode.Cf)
+}
+
+func isMidLetter(r rune) bool {
+	return r == '\'' || r == '.' || r == ':' || r == 0x00B7 || r == 0x2019
+}
+
+func isMidNumber(r rune) bool {
+	return r == ',' || r == '.' || r == ';' || r == '\''
+}
+
+func isAlphanumeric(c wordClass) bool {
+	return c == wordLetter || c == wordNumber
+}
+
+//wordBoundaries returns the rune indices at which runes break into words, spaces and punctuation, from 0 to len(runes)
+//inclusive. it follows the rules of UAX #29 that matter for editing: letters and digits run together, as do
+//contractions such as can't and numbers such as 3.14, while each ideograph stands alone.
+func wordBoundaries(runes []rune) []int {
+	bounds := []int{0}
+	prev := 0 //the last rune that wasn't an extender
+	for i := 1; i < len(runes); i++ {
+		if isWordExtend(runes[i]) {
+			continue
+		}
+		if !joinsWord(runes, prev, i) {
+			bounds = append(bounds, i)
+		}
+		prev = i
+	}
+	if len(runes) > 0 {
+		bounds = append(bounds, len(runes))
+	}
+	return bounds
+}
+
+//joinsWord reports whether runes[i] continues the segment that runes[prev] is in
+func joinsWord(runes []rune, prev, i int) bool {
+	a, b := classifyWord(runes[prev]), classifyWord(runes[i])
+	switch {
+	case runes[prev] == '\r' && runes[i] == '\n':
+		return true
+	case isAlphanumeric(a) && isAlphanumeric(b):
+		return true
+	case a == wordKatakana && b == wordKatakana, a == wordSpace && b == wordSpace:
+		return true
+	}
+	//a letter or number punctuated inside a word joins only when the same kind follows it
+	before, after := prevWordBase(runes, prev), nextWordBase(runes, i)
+	switch {
+	case a == wordLetter && isMidLetter(runes[i]) && after >= 0 && classifyWord(runes[after]) == wordLetter:
+		return true
+	case a == wordNumber && isMidNumber(runes[i]) && after >= 0 && classifyWord(runes[after]) == wordNumber:
+		return true
+	case b == wordLetter && isMidLetter(runes[prev]) && before >= 0 && classifyWord(runes[before]) == wordLetter:
+		return true
+	case b == wordNumber && isMidNumber(runes[prev]) && before >= 0 && classifyWord(runes[before]) == wordNumber:
+		return true
+	}
+	return false
+}
+
+//prevWordBase returns the index of the last rune before i that isn't an extender, or -1
+func prevWordBase(runes []rune, i int) int {
+	for i--; i >= 0; i-- {
+		if !isWordExtend(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+//nextWordBase returns the index of the first rune after i that isn't an extender, or -1
+func nextWordBase(runes []rune, i int) int {
+	for i++; i < len(runes); i++ {
+		if !isWordExtend(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+//isWord reports whether the segment starting with r is a word, rather than spaces or punctuation
+func isWord(r rune) bool {
+	c := classifyWord(r)
+	return c != wordOther && c != wordSpace
+}
+
+//WordBoundaries returns the byte offsets in s at which it breaks into words, spaces and punctuation, starting with 0
+//and ending with len(s)
+func WordBoundaries(s string) []int {
+	runes := []rune(s)
+	bounds := wordBoundaries(runes)
+	offsets := make([]int, len(bounds))
+	offset, r := 0, 0
+	for k, b := range bounds {
+		for ; r < b; r++ {
+			offset += utf8.RuneLen(runes[r])
+		}
+		offsets[k] = offset
+	}
+	return offsets
+}
+
+//wordAt returns the segment of runes [start, end) that holds index, which is the last segment at the end
+func wordAt(runes []rune, index int) (start, end int) {
+	bounds := wordBoundaries(runes)
+	for k := 1; k < len(bounds); k++ {
+		if index < bounds[k] || k == len(bounds)-1 {
+			return bounds[k-1], bounds[k]
+		}
+	}
+	return 0, 0
+}
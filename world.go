@@ -0,0 +1,98 @@
+package gltext
+
+//WorldLabel is text attached to a point in a 3D scene
+type WorldLabel struct {
+	Position [3]float32
+	Text     string
+	Style    Style
+	//Pivot is the point of the label put on the projected position, as fractions of its width and height from
+	//the top left: {0.5, 1} centres it above the point
+	Pivot  [2]float32
+	Offset [2]float32 //pixels to move the label by after projection, with y down
+	//Distance, when set, shrinks and fades the label as it gets further from the camera
+	Distance *DistanceRule
+}
+
+//DistanceRule scales and fades world labels by their distance from the camera, so that far away ones shrink
+//and disappear gracefully rather than popping
+type DistanceRule struct {
+	Reference          float32 //distance at which the label is drawn at the font's own size
+	MinScale, MaxScale float32 //clamps on the scale, which otherwise falls off as Reference over distance
+	FadeStart, FadeEnd float32 //distances over which the label fades out; no fading when FadeEnd is zero
+}
+
+//At returns the scale and opacity of a label at distance from the camera
+func (this DistanceRule) At(distance float32) (scale, alpha float32) {
+	scale = 1
+	if this.Reference > 0 && distance > 0 {
+		scale = this.Reference / distance
+	}
+	if this.MinScale > 0 && scale < this.MinScale {
+		scale = this.MinScale
+	}
+	if this.MaxScale > 0 && scale > this.MaxScale {
+		scale = this.MaxScale
+	}
+	alpha = 1
+	if this.FadeEnd > 0 {
+		switch {
+		case distance >= this.FadeEnd:
+			alpha = 0
+		case distance > this.FadeStart:
+			alpha = (this.FadeEnd - distance) / (this.FadeEnd - this.FadeStart)
+		}
+	}
+	return scale, alpha
+}
+
+//Project maps p through mvp, a column major model view projection matrix as glUniformMatrix4fv takes it,
+//into draw units. ok is false when the point is behind the camera or beyond the far plane.
+func (this *Font) Project(mvp [16]float32, p [3]float32) (x, y float32, ok bool) {
+	x, y, _, ok = project(mvp, p)
+	return x, y, ok
+}
+
+//project also returns the point's clip space w, which under a perspective projection is its distance in front of the camera
+func project(mvp [16]float32, p [3]float32) (x, y, w float32, ok bool) {
+	var clip [4]float32
+	for row := range clip {
+		clip[row] = mvp[row]*p[0] + mvp[4+row]*p[1] + mvp[8+row]*p[2] + mvp[12+row]
+	}
+	w = clip[3]
+	if w <= 0 || clip[2] > w {
+		return 0, 0, w, false
+	}
+	return clip[0]/w + 1, clip[1]/w - 1, w, true
+}
+
+//LayoutWorldLabel lays label out at its projected position, or returns nil if it is behind the camera or has faded out.
+//the result can be drawn straight away or handed to Declutter first.
+func (this *Font) LayoutWorldLabel(mvp [16]float32, label WorldLabel) *Layout {
+	x, y, distance, ok := project(mvp, label.Position)
+	if !ok {
+		return nil
+	}
+	style, scale := label.Style, float32(1)
+	if label.Distance != nil {
+		var alpha float32
+		scale, alpha = label.Distance.At(distance)
+		if alpha <= 0 {
+			return nil
+		}
+		style = style.withAlpha(alpha)
+	}
+	dx, dy := this.PixelToDraw(label.Offset[0], label.Offset[1])
+	layout := this.LayoutStyle(0, 0, style, label.Text)
+	if scale != 1 {
+		layout.Scale(scale)
+	}
+	w, h := layout.Width(), layout.Height()
+	return layout.MoveTo(x+dx-w*label.Pivot[0], y+dy+h*label.Pivot[1])
+}
+
+//DrawWorldLabel draws label at its projected position unless it is behind the camera
+func (this *Font) DrawWorldLabel(mvp [16]float32, label WorldLabel) {
+	if layout := this.LayoutWorldLabel(mvp, label); layout != nil {
+		layout.Draw()
+	}
+}
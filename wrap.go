@@ -0,0 +1,42 @@
+package gltext
+
+//MeasureWrapped returns the size s would take up if laid out by LayoutWrapped in the font's style, and how many
+//lines it would break into, without drawing anything
+func (this *Font) MeasureWrapped(s string, maxWidth float32) (w, h float32, lines int) {
+	layout := this.LayoutWrapped(0, 0, maxWidth, this.style, s)
+	return layout.Width(), layout.Height(), len(layout.lines)
+}
+
+//WrapText breaks s into lines the way LayoutWrapped would, without their newlines or the spaces left hanging at
+//the ends of wrapped lines, so that callers can page through dialogue a screenful at a time
+func (this *Font) WrapText(s string, maxWidth float32) []string {
+	ranges := this.WrapRanges(s, maxWidth)
+	lines := make([]string, len(ranges))
+	for i, r := range ranges {
+		lines[i] = s[r[0]:r[1]]
+	}
+	return lines
+}
+
+//WrapRanges is like WrapText but returns the byte offsets of each line's start and end within s
+func (this *Font) WrapRanges(s string, maxWidth float32) [][2]int {
+	layout := this.LayoutWrapped(0, 0, maxWidth, this.style, s)
+	glyphs := layout.glyphs
+	ranges := make([][2]int, 0, len(layout.lines))
+	for _, line := range layout.lines {
+		end := line.end
+		for end > line.first && (isLineBreak(glyphs[end-1].r) || glyphs[end-1].r == ' ') {
+			end--
+		}
+		start := len(s)
+		if line.first < len(glyphs) {
+			start = glyphs[line.first].byteOffset
+		}
+		stop := start
+		if end > line.first {
+			stop = glyphs[end-1].byteOffset + len(string(glyphs[end-1].r))
+		}
+		ranges = append(ranges, [2]int{start, stop})
+	}
+	return ranges
+}
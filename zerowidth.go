@@ -0,0 +1,17 @@
+package gltext
+
+//ZeroWidthSpace is drawn as nothing, but lets LayoutWrapped break a line there
+const ZeroWidthSpace = '\u200B'
+
+//isZeroWidth reports whether r is only there to steer shaping or line breaking: the zero width space, joiners and
+//non-joiners, the word joiner, the byte order mark and the variation selectors. text copied from the web is full of
+//them, and they take up no room whether or not the font has a glyph for them.
+func isZeroWidth(r rune) bool {
+	switch {
+	case r >= 0x200B && r <= 0x200D, r == 0x2060, r == 0xFEFF:
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0100 && r <= 0xE01EF:
+		return true
+	}
+	return false
+}